@@ -2,6 +2,7 @@ package goapidoc
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +12,8 @@ const (
 	apiPrimeKind apiTypeKind = iota + 1
 	apiObjectKind
 	apiArrayKind
+	apiMapKind
+	apiUnionKind
 )
 
 // Example: Obj<integer#int64, string[]>[]
@@ -21,6 +24,8 @@ type apiType struct {
 	prime  *apiPrime
 	object *apiObject
 	array  *apiArray
+	mapp   *apiMap
+	union  *apiUnion
 }
 
 // Example: string or string#date-time
@@ -40,16 +45,107 @@ type apiArray struct {
 	item *apiType
 }
 
+// Example: map<string, xxx>
+type apiMap struct {
+	key   *apiType
+	value *apiType
+}
+
+// Example: xxx|yyy or oneOf<xxx, yyy> or anyOf<xxx, yyy>
+type apiUnion struct {
+	typ   string // oneOf or anyOf
+	items []*apiType
+}
+
+// Example: T or T: object or T: primitive = string
+type apiGeneric struct {
+	name       string
+	constraint string // object or primitive
+	defaul     string
+}
+
+// maxSpecDepth is the max depth of specializing generic definitions in prehandleDefinitionList, which is only a guard for the pathological
+// nesting, notes that the recursive generic definitions are detected by the specializing stack rather than this depth.
+const maxSpecDepth = 256
+
+// specFrame represents a generic definition being specialized in prehandleDefinitionList.
+type specFrame struct {
+	name    string     // generic definition name
+	args    []*apiType // resolved generic arguments
+	derived bool       // whether the type is derived from the generic parameters of outer definition
+}
+
 var (
 	typeNameRe    = regexp.MustCompile(`^[a-zA-Z0-9_]+(?:(?:<(.+)>)|(?:#[a-zA-Z0-9\-_]*))?(?:\[])*$`) // xxx(?:<(yyy)>|#zzz)?(?:[])*
 	genericNameRe = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	safeGenericRe = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+	genericDeclRe = regexp.MustCompile(`^([a-zA-Z0-9_]+)\s*(?::\s*([a-zA-Z]+))?\s*(?:=\s*(\S.*))?$`) // xxx(?:: yyy)?(?:= zzz)?
 )
 
+// GenericNamingFunc represents a naming strategy of specialized generic definitions, it builds the definition name from the generic
+// definition name and the named generic arguments, such as `_Result` and `["_Page_UserDto_"]` -> `_Result_Page_UserDto_`.
+type GenericNamingFunc func(base string, args []string) string
+
+// safeGenericArg replaces the unsafe characters in given generic argument name, such as `UserDto[]` -> `UserDtoArray`.
+func safeGenericArg(arg string) string {
+	arg = strings.ReplaceAll(arg, "[]", "Array")
+	return strings.Trim(safeGenericRe.ReplaceAllString(arg, "_"), "_")
+}
+
+// OfGenericNaming is a GenericNamingFunc which names specialized generic definitions such as `_Result<_Page<UserDto>>` to
+// `ResultOfPageOfUserDto`, notes that the leading underscores are trimmed and multiple arguments are joined by `And`.
+func OfGenericNaming(base string, args []string) string {
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		names = append(names, strings.TrimLeft(safeGenericArg(arg), "_"))
+	}
+	return strings.TrimLeft(base, "_") + "Of" + strings.Join(names, "And")
+}
+
+// UnderscoreGenericNaming is a GenericNamingFunc which names specialized generic definitions such as `_Result<_Page<UserDto>>` to
+// `_Result_Page_UserDto_`, notes that the generic arguments are wrapped by underscores instead of angle brackets.
+func UnderscoreGenericNaming(base string, args []string) string {
+	name := base
+	for _, arg := range args {
+		name = strings.TrimSuffix(name, "_") + "_" + strings.TrimLeft(safeGenericArg(arg), "_")
+	}
+	return strings.TrimSuffix(name, "_") + "_"
+}
+
+// splitUnionTypes splits given type name by the top-level `|`, such as `A<B|C>|D` -> `A<B|C>` and `D`.
+func splitUnionTypes(typ string) []string {
+	out := make([]string, 0, 1)
+	depth, start := 0, 0
+	for idx, ch := range typ {
+		switch ch {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '|':
+			if depth == 0 {
+				out = append(out, typ[start:idx])
+				start = idx + 1
+			}
+		}
+	}
+	return append(out, typ[start:])
+}
+
 // checkTypeName checks given type name from Param, Response and Definition.
-func checkTypeName(typ string) {
+func checkTypeName(typ string) error {
+	// X|Y
+	if parts := splitUnionTypes(typ); len(parts) > 1 {
+		for _, part := range parts {
+			if err := checkTypeName(strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	// re
 	if !typeNameRe.MatchString(typ) {
-		panic("Invalid type `" + typ + "`")
+		return newDocumentError("Invalid type `" + typ + "`")
 	}
 	// <(.+)>
 	for _, subTyp := range typeNameRe.FindStringSubmatch(typ)[1:] {
@@ -64,30 +160,58 @@ func checkTypeName(typ string) {
 				if idx+1 < len(genParts) {
 					genParts[idx+1] = genParts[idx] + "," + genParts[idx+1]
 				} else {
-					panic("Invalid type `" + typ + "`")
+					return newDocumentError("Invalid type `" + typ + "`")
 				}
 			}
 		}
 		for _, genTyp := range genStrings {
 			if genTyp = strings.TrimSpace(genTyp); genTyp != "" {
-				checkTypeName(genTyp)
+				if err := checkTypeName(genTyp); err != nil {
+					return err
+				}
 			}
 		}
 	}
+	return nil
+}
+
+// checkApiType checks given type name and parses it.
+func checkApiType(typ string) error {
+	if err := checkTypeName(typ); err != nil {
+		return err
+	}
+	_, err := parseApiType(typ)
+	return err
 }
 
-// parseApiType parses type string to three kinds of apiType.
-func parseApiType(typ string) *apiType {
+// parseApiType parses type string to five kinds of apiType.
+func parseApiType(typ string) (*apiType, error) {
 	typ = strings.TrimSpace(typ)
 
+	// 0. union: X|Y | X|Y<Z>[]
+	if parts := splitUnionTypes(typ); len(parts) > 1 {
+		items := make([]*apiType, 0, len(parts))
+		for _, part := range parts {
+			item, err := parseApiType(part)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return newUnionApiType(typ, ONEOF, items)
+	}
+
 	// 1. array: X[] | X[][][]
 	if strings.HasSuffix(typ, "[]") {
-		item := parseApiType(typ[:len(typ)-2]) // X | X[][]
+		item, err := parseApiType(typ[:len(typ)-2]) // X | X[][]
+		if err != nil {
+			return nil, err
+		}
 		return &apiType{
 			name:  typ,
 			kind:  apiArrayKind,
 			array: &apiArray{item: item},
-		}
+		}, nil
 	}
 
 	// 2. prime with format: X# | X#Y
@@ -95,7 +219,7 @@ func parseApiType(typ string) *apiType {
 		for _, prime := range []string{INTEGER, NUMBER, STRING, BOOLEAN, FILE, ARRAY, OBJECT} {
 			if strings.HasPrefix(typ, prime) {
 				if prime == ARRAY || prime == OBJECT {
-					panic("Use array or object in type `" + typ + "` invalidly")
+					return nil, newDocumentError("Use array or object in type `" + typ + "` invalidly")
 				}
 				fmtIdx := strings.Index(typ, "#") + 1
 				fmt := ""
@@ -106,7 +230,7 @@ func parseApiType(typ string) *apiType {
 					name:  typ,
 					kind:  apiPrimeKind,
 					prime: &apiPrime{typ: prime, format: fmt},
-				}
+				}, nil
 			}
 		}
 	}
@@ -124,24 +248,43 @@ func parseApiType(typ string) *apiType {
 				if idx+1 < len(genParts) {
 					genParts[idx+1] = genParts[idx] + "," + genParts[idx+1] // -> Z<A + , + B<C>>
 				} else {
-					panic("Invalid type `" + typ + "`")
+					return nil, newDocumentError("Invalid type `" + typ + "`")
 				}
 			}
 		}
 		genTypes := make([]*apiType, 0, len(genStrings))
 		for _, gen := range genStrings {
-			genTypes = append(genTypes, parseApiType(gen))
+			genType, err := parseApiType(gen)
+			if err != nil {
+				return nil, err
+			}
+			genTypes = append(genTypes, genType)
 		}
 		object := typ[:genIdx-1]
 		switch object {
 		case INTEGER, NUMBER, STRING, BOOLEAN, FILE, ARRAY, OBJECT:
-			panic("Invalid type `" + typ + "`")
+			return nil, newDocumentError("Invalid type `" + typ + "`")
+		case MAP:
+			if len(genTypes) != 2 {
+				return nil, newDocumentError("Map type `" + typ + "` must have key and value types")
+			}
+			key := genTypes[0]
+			if key.kind != apiPrimeKind || key.prime.typ == FILE || key.prime.typ == OBJECT {
+				return nil, newDocumentError("Map type `" + typ + "`'s key type must be primitive")
+			}
+			return &apiType{
+				name: typ,
+				kind: apiMapKind,
+				mapp: &apiMap{key: key, value: genTypes[1]},
+			}, nil
+		case ONEOF, ANYOF:
+			return newUnionApiType(typ, object, genTypes)
 		}
 		return &apiType{
 			name:   typ,
 			kind:   apiObjectKind,
 			object: &apiObject{typ: object, generics: genTypes},
-		}
+		}, nil
 	}
 
 	// 4. prime without format: X
@@ -151,22 +294,261 @@ func parseApiType(typ string) *apiType {
 			name:  typ,
 			kind:  apiPrimeKind,
 			prime: &apiPrime{typ: typ, format: defaultFormat(typ)},
-		}
-	case ARRAY, OBJECT:
-		panic("Use array or object as type invalidly")
+		}, nil
+	case OBJECT:
+		return &apiType{
+			name:  typ,
+			kind:  apiPrimeKind,
+			prime: &apiPrime{typ: typ}, // free-form object
+		}, nil
+	case ARRAY:
+		return nil, newDocumentError("Use array as type invalidly")
+	case MAP:
+		return nil, newDocumentError("Use map without key and value types invalidly")
+	case ONEOF, ANYOF:
+		return nil, newDocumentError("Use " + typ + " without item types invalidly")
 	}
 
 	// 5. object without generic: X
 	if strings.Contains(typ, "#") {
-		panic("Invalid type `" + typ + "`")
+		return nil, newDocumentError("Invalid type `" + typ + "`")
 	}
 	return &apiType{
 		name:   typ,
 		kind:   apiObjectKind,
 		object: &apiObject{typ: typ, generics: []*apiType{}},
+	}, nil
+}
+
+// newUnionApiType checks the union items and creates an apiType in union kind.
+func newUnionApiType(typ, unionTyp string, items []*apiType) (*apiType, error) {
+	if len(items) < 2 {
+		return nil, newDocumentError("Union type `" + typ + "` must have at least two item types")
+	}
+	for _, item := range items {
+		if item.kind == apiPrimeKind && item.prime.typ == FILE {
+			return nil, newDocumentError("Invalid file type used in union type `" + typ + "`")
+		}
+		if item.kind == apiPrimeKind && item.prime.typ == OBJECT {
+			return nil, newDocumentError("Invalid free-form object type used in union type `" + typ + "`")
+		}
+	}
+	return &apiType{
+		name:  typ,
+		kind:  apiUnionKind,
+		union: &apiUnion{typ: unionTyp, items: items},
+	}, nil
+}
+
+// innerApiType returns the innermost item or value type of given array or map type, or returns itself for other types.
+func innerApiType(at *apiType) *apiType {
+	for {
+		switch at.kind {
+		case apiArrayKind:
+			at = at.array.item
+		case apiMapKind:
+			at = at.mapp.value
+		default:
+			return at
+		}
 	}
 }
 
+// parseApiGeneric parses given generic declaration from Definition, such as `T`, `T: object`, `T = UserDto` and `T: primitive = string`.
+func parseApiGeneric(decl string) (*apiGeneric, error) {
+	m := genericDeclRe.FindStringSubmatch(strings.TrimSpace(decl))
+	if m == nil {
+		return nil, newDocumentError("Invalid generic type `" + decl + "`")
+	}
+	gen := &apiGeneric{name: m[1], constraint: m[2], defaul: strings.TrimSpace(m[3])}
+	if gen.constraint != "" && gen.constraint != OBJECT && gen.constraint != PRIMITIVE {
+		return nil, newDocumentError("Generic type `" + gen.name + "`'s constraint `" + gen.constraint + "` is not supported")
+	}
+	if gen.defaul != "" {
+		if err := checkApiType(gen.defaul); err != nil {
+			return nil, errorInField(err, gen.name)
+		}
+	}
+	return gen, nil
+}
+
+// parseApiGenerics parses and deduplicates given generic declarations from Definition.
+func parseApiGenerics(decls []string) ([]*apiGeneric, error) {
+	out := make([]*apiGeneric, 0, len(decls))
+	for _, decl := range decls {
+		gen, err := parseApiGeneric(decl)
+		if err != nil {
+			return nil, err
+		}
+		contained := false
+		for _, g := range out {
+			if g.name == gen.name {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			out = append(out, gen)
+		}
+	}
+	return out, nil
+}
+
+// resolveGenericArguments returns the generic arguments of given object type with the omitted ones filled by defaults. Notes that trailing
+// arguments can be omitted only if they have defaults, and if all arguments are omitted, the ones without default are filled by `object`.
+func resolveGenericArguments(at *apiType, generics []*apiGeneric) ([]*apiType, error) {
+	args := at.object.generics
+	if len(args) > len(generics) {
+		return nil, newDocumentError("Object type `" + at.name + "`'s generic parameter length is not matched")
+	}
+	out := make([]*apiType, 0, len(generics))
+	for idx, gen := range generics {
+		if idx < len(args) {
+			out = append(out, args[idx])
+			continue
+		}
+		typ := gen.defaul
+		if typ == "" {
+			if len(args) > 0 {
+				return nil, newDocumentError("Object type `" + at.name + "` misses generic parameter `" + gen.name + "` which has no default")
+			}
+			typ = OBJECT
+		}
+		arg, err := parseApiType(typ)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, arg)
+	}
+	return out, nil
+}
+
+// checkGenericConstraint checks whether given generic argument satisfies the constraint of given generic parameter, notes that enum
+// definitions are regarded as primitive types.
+func checkGenericConstraint(typ string, gen *apiGeneric, arg *apiType, defMap map[string]*Definition) error {
+	isPrime := arg.kind == apiPrimeKind && arg.prime.typ != OBJECT && arg.prime.typ != FILE
+	isObject := arg.kind == apiPrimeKind && arg.prime.typ == OBJECT
+	if arg.kind == apiObjectKind {
+		if def, ok := defMap[arg.object.typ]; ok && def.enumType != "" {
+			isPrime = true
+		} else {
+			isObject = true
+		}
+	}
+	if gen.constraint == OBJECT && !isObject {
+		return newDocumentError("Object type `" + typ + "`'s generic parameter `" + gen.name + "` must be an object type")
+	}
+	if gen.constraint == PRIMITIVE && !isPrime {
+		return newDocumentError("Object type `" + typ + "`'s generic parameter `" + gen.name + "` must be a primitive type")
+	}
+	return nil
+}
+
+// nameApiType renders given apiType to a type string, in which the specialized generic object types are named by given GenericNamingFunc,
+// notes that union types are rendered as `oneOf<xxx, yyy>` or `anyOf<xxx, yyy>`.
+func nameApiType(at *apiType, naming GenericNamingFunc) string {
+	switch at.kind {
+	case apiArrayKind:
+		return nameApiType(at.array.item, naming) + "[]"
+	case apiMapKind:
+		return MAP + "<" + nameApiType(at.mapp.key, naming) + ", " + nameApiType(at.mapp.value, naming) + ">"
+	case apiUnionKind:
+		items := make([]string, 0, len(at.union.items))
+		for _, item := range at.union.items {
+			items = append(items, nameApiType(item, naming))
+		}
+		return at.union.typ + "<" + strings.Join(items, ", ") + ">"
+	case apiObjectKind:
+		if len(at.object.generics) == 0 {
+			return at.object.typ
+		}
+		args := make([]string, 0, len(at.object.generics))
+		for _, arg := range at.object.generics {
+			args = append(args, nameApiType(arg, naming))
+		}
+		return naming(at.object.typ, args)
+	}
+	return at.name
+}
+
+// keepGenericNaming is a GenericNamingFunc which keeps the specialized generic definition names, such as `_Result<_Page<UserDto>>`.
+func keepGenericNaming(base string, args []string) string {
+	return base + "<" + strings.Join(args, ", ") + ">"
+}
+
+// nameTypeString names the specialized generic object types in given type string by given GenericNamingFunc, returns the type string itself
+// if the naming strategy is nil or the type is invalid.
+func nameTypeString(typ string, naming GenericNamingFunc) string {
+	if naming == nil {
+		return typ
+	}
+	if err := checkApiType(typ); err != nil {
+		return typ
+	}
+	at, _ := parseApiType(typ)
+	return nameApiType(at, naming)
+}
+
+// genericNamingOf returns the generic naming strategy from given Document's option, keepGenericNaming is used by default, so that the
+// generic arguments are always rendered in the same form, such as `Box<oneOf<Cat, Dog>>` for `Box<Cat|Dog>`.
+func genericNamingOf(doc *Document) GenericNamingFunc {
+	if doc.option == nil || doc.option.genericNaming == nil {
+		return keepGenericNaming
+	}
+	return doc.option.genericNaming
+}
+
+// nameGenericProperties returns the cloned properties whose types are named by given GenericNamingFunc, including the properties of
+// inline objects recursively.
+func nameGenericProperties(properties []*Property, naming GenericNamingFunc) []*Property {
+	out := make([]*Property, 0, len(properties))
+	for _, prop := range properties {
+		cloned := cloneProperty(prop)
+		walkProperties([]*Property{cloned}, func(p *Property) {
+			p.typ = nameTypeString(p.typ, naming)
+		})
+		out = append(out, cloned)
+	}
+	return out
+}
+
+// nameGenericParams returns the params whose types are named by given GenericNamingFunc, the params are cloned if the strategy is not nil.
+func nameGenericParams(params []*Param, naming GenericNamingFunc) []*Param {
+	if naming == nil {
+		return params
+	}
+	out := make([]*Param, 0, len(params))
+	for _, p := range params {
+		cloned := *p
+		cloned.typ = nameTypeString(p.typ, naming)
+		if p.inlineObject != nil {
+			cloned.inlineObject = &InlineObject{properties: nameGenericProperties(p.inlineObject.properties, naming)}
+		}
+		out = append(out, &cloned)
+	}
+	return out
+}
+
+// nameGenericResponses returns the responses whose types are named by given GenericNamingFunc, the responses are cloned if the strategy
+// is not nil.
+func nameGenericResponses(responses []*Response, naming GenericNamingFunc) []*Response {
+	if naming == nil {
+		return responses
+	}
+	out := make([]*Response, 0, len(responses))
+	for _, r := range responses {
+		cloned := *r
+		if r.typ != "" {
+			cloned.typ = nameTypeString(r.typ, naming)
+		}
+		if r.inlineObject != nil {
+			cloned.inlineObject = &InlineObject{properties: nameGenericProperties(r.inlineObject.properties, naming)}
+		}
+		out = append(out, &cloned)
+	}
+	return out
+}
+
 // defaultFormat returns the default format for given type.
 func defaultFormat(typ string) string {
 	if typ == INTEGER {
@@ -178,27 +560,132 @@ func defaultFormat(typ string) string {
 	return ""
 }
 
+// parsePrimeValue parses given value string to the value of given prime type, returns the string itself if failed.
+func parsePrimeValue(s string, typ string) interface{} {
+	switch strings.SplitN(typ, "#", 2)[0] {
+	case INTEGER:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case NUMBER:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case BOOLEAN:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
+
+// inlineObjectDepth returns the array depth of given type which is used with an inline object, such as `object[][]` -> 2, and returns -1
+// if the type is not object or array of object.
+func inlineObjectDepth(typ string) int {
+	typ = strings.TrimSpace(typ)
+	depth := 0
+	for strings.HasSuffix(typ, "[]") {
+		typ = typ[:len(typ)-2]
+		depth++
+	}
+	if typ != OBJECT {
+		return -1
+	}
+	return depth
+}
+
+// checkInlineObjectType checks given type which is used with an inline object.
+func checkInlineObjectType(typ string) error {
+	if inlineObjectDepth(typ) == -1 {
+		return newDocumentError("Inline object must be used with object type, such as `object` or `object[]`")
+	}
+	return nil
+}
+
+// checkPropertyTypes checks the types of given properties, including the properties of inline objects recursively.
+func checkPropertyTypes(properties []*Property) error {
+	for _, prop := range properties {
+		if prop.inlineObject == nil {
+			if err := checkApiType(prop.typ); err != nil {
+				return errorInField(err, prop.name)
+			}
+			continue
+		}
+		if err := checkInlineObjectType(prop.typ); err != nil {
+			return errorInField(err, prop.name)
+		}
+		if err := checkPropertyTypes(prop.inlineObject.properties); err != nil {
+			return errorInField(err, prop.name)
+		}
+	}
+	return nil
+}
+
+// walkProperties visits given properties and the properties of inline objects recursively.
+func walkProperties(properties []*Property, fn func(prop *Property)) {
+	for _, prop := range properties {
+		fn(prop)
+		if prop.inlineObject != nil {
+			walkProperties(prop.inlineObject.properties, fn)
+		}
+	}
+}
+
+// collectPropertyTypes collects the types of given properties, including the properties of inline objects recursively.
+func collectPropertyTypes(properties []*Property) []string {
+	out := make([]string, 0, len(properties))
+	walkProperties(properties, func(prop *Property) {
+		if prop.inlineObject == nil {
+			out = append(out, prop.typ)
+		}
+	})
+	return out
+}
+
 // collectAllSpecTypes checks and collects all specific types.
-func collectAllSpecTypes(doc *Document) []string {
+func collectAllSpecTypes(doc *Document) ([]string, error) {
 	// check all type names (param, resp, prop)
 	cnt := 0
 	for _, op := range doc.operations {
 		cnt += len(op.params) + len(op.responses)
 		for _, param := range op.params {
-			checkTypeName(param.typ)
+			err := checkApiType(param.typ)
+			if param.inlineObject != nil {
+				err = checkInlineObjectType(param.typ)
+				if err == nil {
+					err = checkPropertyTypes(param.inlineObject.properties)
+				}
+			}
+			if err != nil {
+				return nil, errorInOperation(errorInField(err, param.name), op)
+			}
 		}
 		for _, resp := range op.responses {
-			if resp.typ != "" {
-				checkTypeName(resp.typ)
+			var err error
+			if resp.inlineObject != nil {
+				err = checkInlineObjectType(resp.typ)
+				if err == nil {
+					err = checkPropertyTypes(resp.inlineObject.properties)
+				}
+			} else if resp.typ != "" {
+				err = checkApiType(resp.typ)
+			}
+			if err != nil {
+				return nil, errorInOperation(errorInField(err, strconv.Itoa(resp.code)), op)
 			}
 		}
 	}
 	for _, def := range doc.definitions {
-		for _, prop := range def.properties {
-			checkTypeName(prop.typ)
+		if err := checkPropertyTypes(def.properties); err != nil {
+			return nil, errorInDefinition(err, def.name)
+		}
+		for _, ext := range def.extends {
+			if err := checkExtendedType(ext); err != nil {
+				return nil, errorInDefinition(errorInField(err, ext), def.name)
+			}
 		}
 		if len(def.generics) == 0 {
-			cnt += len(def.properties)
+			cnt += len(def.properties) + len(def.extends)
 		}
 	}
 
@@ -206,51 +693,101 @@ func collectAllSpecTypes(doc *Document) []string {
 	out := make([]string, 0, cnt)
 	for _, op := range doc.operations {
 		for _, param := range op.params {
-			out = append(out, param.typ)
+			if param.inlineObject != nil {
+				out = append(out, collectPropertyTypes(param.inlineObject.properties)...)
+			} else {
+				out = append(out, param.typ)
+			}
 		}
 		for _, resp := range op.responses {
-			if resp.typ != "" {
+			if resp.inlineObject != nil {
+				out = append(out, collectPropertyTypes(resp.inlineObject.properties)...)
+			} else if resp.typ != "" {
 				out = append(out, resp.typ)
 			}
 		}
 	}
 	for _, def := range doc.definitions {
 		if len(def.generics) == 0 {
-			for _, prop := range def.properties {
-				out = append(out, prop.typ)
-			}
+			out = append(out, collectPropertyTypes(def.properties)...)
+			out = append(out, def.extends...)
 		}
 	}
-	return out
+	return out, nil
 }
 
-// prehandleDefinition deduplicates, checks and prehandles generic names, and returns a new cloned Definition.
-func prehandleDefinition(definition *Definition) *Definition {
-	// deduplicate and check generic names
-	generics := make([]string, 0, len(definition.generics))
-	for _, gen := range definition.generics {
-		contained := false
-		for _, g := range generics {
-			if g == gen {
-				contained = true
-				break
-			}
+// collectEnumDefinitions collects the enum definitions from given definitions, and returns a map from definition name to Definition.
+func collectEnumDefinitions(definitions []*Definition) map[string]*Definition {
+	out := make(map[string]*Definition)
+	for _, def := range definitions {
+		if def.enumType != "" {
+			out[def.name] = def
 		}
-		if !contained {
-			if !genericNameRe.MatchString(gen) { // a-zA-Z0-9_
-				panic("Invalid generic type `" + gen + "`")
-			}
-			generics = append(generics, gen)
+	}
+	return out
+}
+
+// checkExtendedType checks the given type which is extended by a definition, only object types are allowed.
+func checkExtendedType(typ string) error {
+	if err := checkApiType(typ); err != nil {
+		return err
+	}
+	at, err := parseApiType(typ)
+	if err != nil {
+		return err
+	}
+	if at.kind != apiObjectKind {
+		return newDocumentError("Extended type `" + typ + "` must be an object type")
+	}
+	return nil
+}
+
+// prehandleAllDefinitions checks all types and prehandles all definitions of given Document, and returns the final Definition list, the
+// extra types are also used to specialize the generic definitions.
+func prehandleAllDefinitions(doc *Document, extraTypes ...string) ([]*Definition, error) {
+	allSpecTypes, err := collectAllSpecTypes(doc)
+	if err != nil {
+		return nil, err
+	}
+	allSpecTypes = append(allSpecTypes, extraTypes...)
+	clonedDefinitions := make([]*Definition, 0, len(doc.definitions))
+	for _, definition := range doc.definitions {
+		cloned, err := prehandleDefinition(definition) // with generic name checked
+		if err != nil {
+			return nil, err
 		}
+		clonedDefinitions = append(clonedDefinitions, cloned)
+	}
+	return prehandleDefinitionList(clonedDefinitions, allSpecTypes, genericNamingOf(doc))
+}
+
+// prehandleDefinition deduplicates, checks and prehandles generic names, and returns a new cloned Definition.
+func prehandleDefinition(definition *Definition) (*Definition, error) {
+	// deduplicate and check generic declarations
+	genericParams, err := parseApiGenerics(definition.generics)
+	if err != nil {
+		return nil, errorInDefinition(err, definition.name)
+	}
+	generics := make([]string, 0, len(genericParams))
+	for _, gen := range genericParams {
+		generics = append(generics, gen.name)
 	}
 
 	// clone definition
 	out := &Definition{
-		name:       definition.name,
-		desc:       definition.desc,
-		xmlRepr:    definition.xmlRepr,
-		generics:   generics,
-		properties: make([]*Property, 0, len(definition.properties)),
+		name:          definition.name,
+		desc:          definition.desc,
+		xmlRepr:       definition.xmlRepr,
+		generics:      generics,
+		genericParams: genericParams,
+		extends:       append([]string{}, definition.extends...),
+		discriminator: definition.discriminator,
+		mapping:       definition.mapping,
+		properties:    make([]*Property, 0, len(definition.properties)),
+		enumType:      definition.enumType,
+		enums:         definition.enums,
+		enumVarNames:  definition.enumVarNames,
+		enumDescs:     definition.enumDescs,
 	}
 	for _, prop := range definition.properties {
 		out.properties = append(out.properties, cloneProperty(prop))
@@ -259,32 +796,87 @@ func prehandleDefinition(definition *Definition) *Definition {
 	// update generic type name
 	for idx, gen := range out.generics {
 		newGen := "«" + gen + "»"
-		re := regexp.MustCompile(`(^|[,\s<])` + gen + `([,\s<>\[]|$)`) // {,\s<} xxx {,\s<>[}
-		for _, prop := range out.properties {
-			for { // replace all property type
-				curr := prop.typ
-				prop.typ = strings.ReplaceAll(prop.typ, " ", "")
-				prop.typ = re.ReplaceAllString(prop.typ, "$1"+newGen+"$2") // T -> «T»
-				prop.typ = strings.ReplaceAll(prop.typ, ",", ", ")
-				if prop.typ == curr {
-					break
+		re := regexp.MustCompile(`(^|[,\s<|])` + gen + `([,\s<>\[|]|$)`) // {,\s<|} xxx {,\s<>[|}
+		renameFn := func(typ string) string {
+			for { // replace all generic type names
+				curr := typ
+				typ = strings.ReplaceAll(typ, " ", "")
+				typ = re.ReplaceAllString(typ, "$1"+newGen+"$2") // T -> «T»
+				typ = strings.ReplaceAll(typ, ",", ", ")
+				if typ == curr {
+					return typ
 				}
 			}
 		}
+		walkProperties(out.properties, func(prop *Property) {
+			prop.typ = renameFn(prop.typ)
+		})
+		for i, ext := range out.extends {
+			out.extends[i] = renameFn(ext)
+		}
 		out.generics[idx] = newGen
 	}
-	return out
+	return out, nil
+}
+
+// isGrowingSpec checks whether specializing given generic definition with given arguments grows from an outer specialization of the same
+// definition in stack, such as `Node<string[]>` from `Node<string>` when `Node<T>` has `next: Node<T[]>`, which will expand infinitely.
+// Notes that all the specializations between them must be derived from the generic parameters, otherwise the arguments are fixed.
+func isGrowingSpec(stack []*specFrame, name string, args []*apiType) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		frame := stack[i]
+		if frame.name == name && len(frame.args) == len(args) {
+			growing := true
+			for idx, arg := range args {
+				growing = growing && containsApiType(arg, frame.args[idx])
+			}
+			if growing {
+				return true
+			}
+		}
+		if !frame.derived {
+			return false
+		}
+	}
+	return false
 }
 
-// prehandleDefinitionList prehandles and returns the final Definition list with given and type list.
-func prehandleDefinitionList(allDefinitions []*Definition, allTypes []string) []*Definition {
+// containsApiType checks whether given apiType is or contains the target apiType.
+func containsApiType(at, target *apiType) bool {
+	if at.name == target.name {
+		return true
+	}
+	switch at.kind {
+	case apiArrayKind:
+		return containsApiType(at.array.item, target)
+	case apiMapKind:
+		return containsApiType(at.mapp.key, target) || containsApiType(at.mapp.value, target)
+	case apiUnionKind:
+		for _, item := range at.union.items {
+			if containsApiType(item, target) {
+				return true
+			}
+		}
+	case apiObjectKind:
+		for _, arg := range at.object.generics {
+			if containsApiType(arg, target) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prehandleDefinitionList prehandles and returns the final Definition list with given and type list, the specialized generic definitions
+// are named by given GenericNamingFunc if it is not nil.
+func prehandleDefinitionList(allDefinitions []*Definition, allTypes []string, naming GenericNamingFunc) ([]*Definition, error) {
 	// extract generic definitions from given definitions
 	allDefMap := make(map[string]*Definition, len(allDefinitions))
 	out := make([]*Definition, 0, len(allDefinitions))    // out definition slice
 	outKeys := make(map[string]bool, len(allDefinitions)) // out definition key map
 	for _, def := range allDefinitions {
 		if _, ok := allDefMap[def.name]; ok {
-			panic("Duplicate definition `" + def.name + "`")
+			return nil, newDocumentError("Duplicate definition `" + def.name + "`")
 		}
 		allDefMap[def.name] = def
 		if len(def.generics) == 0 {
@@ -294,17 +886,27 @@ func prehandleDefinitionList(allDefinitions []*Definition, allTypes []string) []
 	}
 
 	// extract more definitions from given types
-	var extractFn func(typ string)
-	extractFn = func(typ string) {
+	stack := make([]*specFrame, 0, 4) // the specializing definitions
+	var extractFn func(typ string, derived bool) error
+	extractFn = func(typ string, derived bool) error {
 		if _, ok := outKeys[typ]; ok {
-			return
+			return nil
 		}
-		at := parseApiType(typ)
-		for at.kind == apiArrayKind {
-			at = at.array.item
+		at, err := parseApiType(typ)
+		if err != nil {
+			return err
+		}
+		at = innerApiType(at)
+		if at.kind == apiUnionKind {
+			for _, item := range at.union.items {
+				if err := extractFn(item.name, derived); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
 		if at.kind != apiObjectKind {
-			return
+			return nil
 		}
 		// `at` belongs to object
 		obj := at.object
@@ -312,50 +914,124 @@ func prehandleDefinitionList(allDefinitions []*Definition, allTypes []string) []
 		// check object existence and generic parameter
 		genDef, ok := allDefMap[obj.typ]
 		if !ok {
-			panic("Object type `" + at.name + "` not found")
+			return newDocumentError("Object type `" + at.name + "` not found")
+		}
+		if len(genDef.generics) == 0 {
+			if len(obj.generics) != 0 {
+				return newDocumentError("Object type `" + at.name + "`'s generic parameter length is not matched")
+			}
+			return nil
 		}
-		if len(obj.generics) != len(genDef.generics) {
-			panic("Object type `" + at.name + "`'s generic parameter length is not matched")
+		args, err := resolveGenericArguments(at, genDef.genericParams)
+		if err != nil {
+			return err
 		}
-		if len(obj.generics) == 0 {
-			return
+		for idx, arg := range args {
+			if err := checkGenericConstraint(at.name, genDef.genericParams[idx], arg, allDefMap); err != nil {
+				return err
+			}
 		}
 
+		// check and mark the specific definition before extracting recurrently, notes that the omitted generic arguments are not included
+		// in definition name, and the definition may be referenced by itself or the definitions it references
+		specDefName := nameApiType(at, keepGenericNaming) // TypeName -> TypeName<GenericName, ...>, in canonical form
+		if _, ok := outKeys[specDefName]; ok {
+			return nil // extracted or being extracted
+		}
+		if derived && isGrowingSpec(stack, genDef.name, args) {
+			return newDocumentError("Object type `" + at.name + "` is recursive with growing generic parameters")
+		}
+		if len(stack) >= maxSpecDepth {
+			return newDocumentError("Object type `" + at.name + "` is nested too deeply")
+		}
+		outKeys[specDefName] = true
+		stack = append(stack, &specFrame{name: genDef.name, args: args, derived: derived})
+		defer func() { stack = stack[:len(stack)-1] }()
+
 		// specific definition need to be added
 		specDef := &Definition{
-			name:       genDef.name, // TypeName<GenericName, ...>
-			desc:       genDef.desc,
-			xmlRepr:    genDef.xmlRepr,
-			generics:   nil, // empty
-			properties: make([]*Property, 0, len(genDef.properties)),
+			name:          specDefName, // TypeName<GenericName, ...>
+			desc:          genDef.desc,
+			xmlRepr:       genDef.xmlRepr,
+			generics:      nil, // empty
+			extends:       append([]string{}, genDef.extends...),
+			discriminator: genDef.discriminator,
+			mapping:       genDef.mapping,
+			properties:    make([]*Property, 0, len(genDef.properties)),
 		}
 		for _, prop := range genDef.properties {
 			specDef.properties = append(specDef.properties, cloneProperty(prop)) // << need to extract type recurrently
 		}
+		derivedProps := make(map[*Property]bool) // properties whose types contain generic parameters
+		walkProperties(specDef.properties, func(prop *Property) {
+			derivedProps[prop] = strings.Contains(prop.typ, "«")
+		})
 		// replace to spec name for new definition
-		specNames := make([]string, 0, len(genDef.generics))
 		for idx, genName := range genDef.generics {
-			specName := obj.generics[idx].name
-			specNames = append(specNames, specName)
-			for _, prop := range specDef.properties {
+			specName := args[idx].name
+			if args[idx].kind == apiUnionKind {
+				specName = nameApiType(args[idx], keepGenericNaming) // X|Y -> oneOf<X, Y>, keep the union when used in T[] or map<K, T>
+			}
+			walkProperties(specDef.properties, func(prop *Property) {
 				prop.typ = strings.ReplaceAll(prop.typ, genName, specName) // «T» -> XXX, replace directly
+			})
+			for i, ext := range specDef.extends {
+				specDef.extends[i] = strings.ReplaceAll(ext, genName, specName)
 			}
 		}
-		specDef.name += "<" + strings.Join(specNames, ", ") + ">" // TypeName -> TypeName<GenericName, ...>
 
 		// extract recurrently and append to outMap
-		for _, prop := range specDef.properties {
-			extractFn(prop.typ) // << extract property type recurrently
+		walkProperties(specDef.properties, func(prop *Property) {
+			if err == nil && prop.inlineObject == nil {
+				if err = extractFn(prop.typ, derivedProps[prop]); err != nil { // << extract property type recurrently
+					err = errorInDefinition(errorInField(err, prop.name), specDef.name)
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+		for idx, ext := range specDef.extends {
+			if err := extractFn(ext, strings.Contains(genDef.extends[idx], "«")); err != nil { // << extract extended type recurrently
+				return errorInDefinition(errorInField(err, ext), specDef.name)
+			}
 		}
-		out = append(out, specDef)
-		outKeys[specDef.name] = true
+		out = append(out, specDef) // outKeys is marked before
+		return nil
 	}
 
 	// for all types, extract generic parameters to definition list
 	for _, typ := range allTypes {
-		extractFn(typ)
+		if err := extractFn(typ, false); err != nil {
+			return nil, err
+		}
+	}
+
+	// name specialized generic definitions and all the types by given naming strategy
+	if naming != nil {
+		origins := make(map[string]string, len(out)) // named name -> origin name
+		for idx, def := range out {
+			named := *def // shallow copy
+			named.name = nameTypeString(def.name, naming)
+			if origin, ok := origins[named.name]; ok {
+				return nil, newDocumentError("Definition `" + def.name + "` is named to `" + named.name + "`, which is duplicate with `" + origin + "`")
+			}
+			origins[named.name] = def.name
+			named.properties = nameGenericProperties(def.properties, naming)
+			named.extends = make([]string, 0, len(def.extends))
+			for _, ext := range def.extends {
+				named.extends = append(named.extends, nameTypeString(ext, naming))
+			}
+			if def.mapping != nil {
+				named.mapping = make(map[string]string, len(def.mapping))
+				for value, typ := range def.mapping {
+					named.mapping[value] = nameTypeString(typ, naming)
+				}
+			}
+			out[idx] = &named
+		}
 	}
 
 	// return definition slice
-	return out
+	return out, nil
 }
@@ -6,8 +6,8 @@ import (
 
 func TestCheckTypeName(t *testing.T) {
 	for _, tc := range []struct {
-		give      string
-		wantPanic bool
+		give    string
+		wantErr bool
 	}{
 		{"", true},
 		{"$", true},
@@ -46,20 +46,26 @@ func TestCheckTypeName(t *testing.T) {
 		{"Object<T1, T2<T3, T4>>", false},
 		{"Object<T1, T2<T3, T4<>", true},
 		{"Object<T1, T2<TT1<integer#int64[]>>, T3<TT2, TT3<TT4, TT5<number#>>[]>[], string#date-time>[][]", false},
+		{"map<string, Object>", false},
+		{"map<string, map<integer#int64, T[]>>[]", false},
+		{"CatDto|DogDto", false},
+		{"Object<A|B>|string[]", false},
+		{"oneOf<A, B<C|D>>", false},
+		{"A|", true},
+		{"A||B", true},
+		{"A|B<C|>", true},
 	} {
 		t.Run(tc.give, func(t *testing.T) {
-			testPanic(t, tc.wantPanic, func() {
-				checkTypeName(tc.give)
-			}, "checkTypeName")
+			testError(t, tc.wantErr, checkTypeName(tc.give), "checkTypeName")
 		})
 	}
 }
 
 func TestParseApiType(t *testing.T) {
 	for _, tc := range []struct {
-		give      string
-		wantPanic bool
-		checkFn   func(*apiType) bool
+		give    string
+		wantErr bool
+		checkFn func(*apiType) bool
 	}{
 		{"integer", false, func(at *apiType) bool {
 			return at.prime.typ == "integer" && at.prime.format == "int32"
@@ -125,6 +131,34 @@ func TestParseApiType(t *testing.T) {
 				at.object.generics[2].array.item.object.generics[1].array.item.object.generics[1].object.generics[0].prime.format == "" &&
 				at.object.generics[3].prime.typ == "string" && at.object.generics[3].prime.format == "date-time"
 		}},
+		{"map<string, Object>", false, func(at *apiType) bool {
+			return at.kind == apiMapKind && at.mapp.key.prime.typ == "string" && at.mapp.value.object.typ == "Object"
+		}},
+		{"map<integer#int64, T<U>[]>[]", false, func(at *apiType) bool {
+			at = at.array.item
+			return at.mapp.key.prime.typ == "integer" && at.mapp.key.prime.format == "int64" &&
+				at.mapp.value.array.item.object.typ == "T" && at.mapp.value.array.item.object.generics[0].object.typ == "U"
+		}},
+		{"map<string, map<string, boolean>>", false, func(at *apiType) bool {
+			return at.mapp.value.kind == apiMapKind && at.mapp.value.mapp.value.prime.typ == "boolean" && innerApiType(at).prime.typ == "boolean"
+		}},
+		{"CatDto | DogDto", false, func(at *apiType) bool {
+			return at.kind == apiUnionKind && at.union.typ == ONEOF && len(at.union.items) == 2 &&
+				at.union.items[0].object.typ == "CatDto" && at.union.items[1].object.typ == "DogDto"
+		}},
+		{"anyOf<string, Object<A|B>[]>", false, func(at *apiType) bool {
+			return at.kind == apiUnionKind && at.union.typ == ANYOF && at.union.items[0].prime.typ == "string" &&
+				at.union.items[1].array.item.object.generics[0].kind == apiUnionKind
+		}},
+		{"map<string, A|B>", false, func(at *apiType) bool {
+			return at.kind == apiMapKind && innerApiType(at).kind == apiUnionKind && innerApiType(at).union.items[1].object.typ == "B"
+		}},
+		{"object", false, func(at *apiType) bool {
+			return at.kind == apiPrimeKind && at.prime.typ == "object" && at.prime.format == ""
+		}},
+		{"Object<object>", false, func(at *apiType) bool {
+			return at.kind == apiObjectKind && at.object.generics[0].kind == apiPrimeKind && at.object.generics[0].prime.typ == "object"
+		}},
 
 		{"integer<Object>", true, nil},
 		{"Object#xxx", true, nil},
@@ -132,14 +166,24 @@ func TestParseApiType(t *testing.T) {
 		{"array", true, nil},
 		{"array[]", true, nil},
 		{"object#", true, nil},
-		{"Object<object>", true, nil},
+		{"map<object, string>", true, nil},
+		{"map", true, nil},
+		{"map<string>", true, nil},
+		{"map<string, T, U>", true, nil},
+		{"map<Object, string>", true, nil},
+		{"map<string[], string>", true, nil},
+		{"map<file, string>", true, nil},
+		{"oneOf", true, nil},
+		{"anyOf<string>", true, nil},
+		{"string|file", true, nil},
+		{"A|object", true, nil},
 	} {
 		t.Run(tc.give, func(t *testing.T) {
-			testPanic(t, tc.wantPanic, func() {
-				if at := parseApiType(tc.give); !tc.checkFn(at) {
-					failNow(t, "parseApiType get a wrong ApiType")
-				}
-			}, "parseApiType")
+			at, err := parseApiType(tc.give)
+			testError(t, tc.wantErr, err, "parseApiType")
+			if err == nil && !tc.checkFn(at) {
+				failNow(t, "parseApiType get a wrong ApiType")
+			}
 		})
 	}
 }
@@ -149,7 +193,7 @@ func TestPrehandleDefinition(t *testing.T) {
 		name          string
 		giveGenerics  []string
 		givePropTypes []string
-		wantPanic     bool
+		wantErr       bool
 		wantGenerics  []string
 		wantPropTypes []string
 	}{
@@ -171,24 +215,30 @@ func TestPrehandleDefinition(t *testing.T) {
 			false, []string{"«T»", "«U»"}, []string{"Obj<«T», «U»[], TT>[]"}},
 		{"parse5", []string{"T", "U", "V"}, []string{"inT[]", "ObjT<inT[], TV[], U<V>>"},
 			false, []string{"«T»", "«U»", "«V»"}, []string{"inT[]", "ObjT<inT[], TV[], «U»<«V»>>"}},
+
+		{"declare1", []string{"T = UserDto"}, []string{"T"}, false, []string{"«T»"}, []string{"«T»"}},
+		{"declare2", []string{"T: object", "U: primitive = string"}, []string{"Obj<T, U>"}, false, []string{"«T»", "«U»"}, []string{"Obj<«T», «U»>"}},
+		{"declare3", []string{"T", "T = UserDto"}, []string{"T[]"}, false, []string{"«T»"}, []string{"«T»[]"}},
+		{"declare4", []string{"T: array"}, []string{}, true, []string{}, []string{}},
+		{"declare5", []string{"T ="}, []string{}, true, []string{}, []string{}},
+		{"declare6", []string{"T = Obj<"}, []string{}, true, []string{}, []string{}},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			testPanic(t, tc.wantPanic, func() {
-				def := &Definition{generics: tc.giveGenerics, properties: make([]*Property, 0, len(tc.givePropTypes))}
-				for _, typ := range tc.givePropTypes {
-					def.properties = append(def.properties, &Property{typ: typ})
-				}
-				prehandled := prehandleDefinition(def)
+			def := &Definition{generics: tc.giveGenerics, properties: make([]*Property, 0, len(tc.givePropTypes))}
+			for _, typ := range tc.givePropTypes {
+				def.properties = append(def.properties, &Property{typ: typ})
+			}
+			prehandled, err := prehandleDefinition(def)
+			testError(t, tc.wantErr, err, "prehandleDefinition")
 
-				if !tc.wantPanic {
-					testMatchElements(t, prehandled.generics, tc.wantGenerics, "prehandledGenerics", "wantGenerics")
-					prehandledProperties := make([]string, 0, len(prehandled.properties))
-					for _, prop := range prehandled.properties {
-						prehandledProperties = append(prehandledProperties, prop.typ)
-					}
-					testMatchElements(t, prehandledProperties, tc.wantPropTypes, "prehandledPropTypes", "wantPropTypes")
+			if !tc.wantErr {
+				testMatchElements(t, prehandled.generics, tc.wantGenerics, "prehandledGenerics", "wantGenerics")
+				prehandledProperties := make([]string, 0, len(prehandled.properties))
+				for _, prop := range prehandled.properties {
+					prehandledProperties = append(prehandledProperties, prop.typ)
 				}
-			}, "prehandleDefinition")
+				testMatchElements(t, prehandledProperties, tc.wantPropTypes, "prehandledPropTypes", "wantPropTypes")
+			}
 		})
 	}
 }
@@ -199,27 +249,32 @@ func TestPrehandleDefinitionList(t *testing.T) {
 		{name: "Result2", generics: []string{"T", "U"}, properties: []*Property{{name: "code", typ: "integer"}, {name: "data", typ: "T"}, {name: "error", typ: "U"}}},
 		{name: "Page", generics: []string{"T"}, properties: []*Property{{name: "total", typ: "integer"}, {name: "data", typ: "T[]"}}},
 		{name: "Page2", generics: []string{"T"}, properties: []*Property{{name: "next_max_id", typ: "integer"}, {name: "total", typ: "integer"}, {name: "data", typ: "T[]"}}},
+		{name: "Result3", generics: []string{"T: object", "U = ErrorDto"}, properties: []*Property{{name: "data", typ: "T"}, {name: "error", typ: "U"}}},
+		{name: "Wrapper", generics: []string{"T: primitive = integer"}, properties: []*Property{{name: "value", typ: "T"}}},
+		{name: "TreeNode", generics: []string{"T"}, properties: []*Property{{name: "value", typ: "T"}, {name: "children", typ: "TreeNode<T>[]"}}},
+		{name: "Comment", generics: []string{"T"}, properties: []*Property{{name: "data", typ: "T"}, {name: "thread", typ: "Thread<T>"}}},
+		{name: "Thread", generics: []string{"T"}, properties: []*Property{{name: "comments", typ: "Comment<T>[]"}}},
+		{name: "Nested", generics: []string{"T"}, properties: []*Property{{name: "next", typ: "Nested<T[]>"}}},
 
 		{name: "UserDto", properties: []*Property{{name: "uid", typ: "integer"}, {name: "name", typ: "string"}}},
 		{name: "ErrorDto", properties: []*Property{{name: "type", typ: "string"}, {name: "detail", typ: "string"}}},
 	}
 	prehandledDefinitions := make([]*Definition, 0, len(definitions))
 	for _, definition := range definitions {
-		prehandledDefinitions = append(prehandledDefinitions, prehandleDefinition(definition))
+		prehandled, _ := prehandleDefinition(definition)
+		prehandledDefinitions = append(prehandledDefinitions, prehandled)
 	}
 
 	t.Run("dup definition", func(t *testing.T) {
-		testPanic(t, true, func() {
-			prehandleDefinitionList([]*Definition{{name: "UserDto"}, {name: "UserDto"}}, []string{})
-		}, "prehandleDefinitionList")
-		testPanic(t, true, func() {
-			prehandleDefinitionList([]*Definition{{name: "Result", generics: []string{"T"}}, {name: "Result"}}, []string{})
-		}, "prehandleDefinitionList")
+		_, err := prehandleDefinitionList([]*Definition{{name: "UserDto"}, {name: "UserDto"}}, []string{}, nil)
+		testError(t, true, err, "prehandleDefinitionList")
+		_, err = prehandleDefinitionList([]*Definition{{name: "Result", generics: []string{"T"}}, {name: "Result"}}, []string{}, nil)
+		testError(t, true, err, "prehandleDefinitionList")
 	})
 	for _, tc := range []struct {
 		name            string
 		giveTypes       []string
-		wantPanic       bool
+		wantErr         bool
 		wantObjectNames []string
 		wantPropNames   [][]string
 		wantPropTypes   [][]string
@@ -274,26 +329,89 @@ func TestPrehandleDefinitionList(t *testing.T) {
 		{"UserDto<integer>", []string{"UserDto<integer>"}, true, nil, nil, nil},
 		{"Result<UserDto, ErrorDto>", []string{"Result<UserDto, ErrorDto>"}, true, nil, nil, nil},
 		{"Result2<UserDto>", []string{"Result2<UserDto>"}, true, nil, nil, nil},
+
+		{"Result", []string{"Result"}, false, []string{"UserDto", "ErrorDto", "Result"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"code", "data"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"integer", "object"}}},
+		{"Result2 | Page<object>", []string{"Result2", "Page<object>"}, false, []string{"UserDto", "ErrorDto", "Result2", "Page<object>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"code", "data", "error"}, {"total", "data"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"integer", "object", "object"}, {"integer", "object[]"}}},
+		{"Result3 | Result3<UserDto> | Result3<UserDto, UserDto>", []string{"Result3", "Result3<UserDto>", "Result3<UserDto, UserDto>"}, false,
+			[]string{"UserDto", "ErrorDto", "Result3", "Result3<UserDto>", "Result3<UserDto, UserDto>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"data", "error"}, {"data", "error"}, {"data", "error"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"object", "ErrorDto"}, {"UserDto", "ErrorDto"}, {"UserDto", "UserDto"}}},
+		{"Wrapper | Wrapper<string>", []string{"Wrapper", "Wrapper<string>"}, false, []string{"UserDto", "ErrorDto", "Wrapper", "Wrapper<string>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"value"}, {"value"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"integer"}, {"string"}}},
+		{"Result3<integer>", []string{"Result3<integer>"}, true, nil, nil, nil},
+		{"Result3<UserDto[]>", []string{"Result3<UserDto[]>"}, true, nil, nil, nil},
+		{"Result3<UserDto, ErrorDto, UserDto>", []string{"Result3<UserDto, ErrorDto, UserDto>"}, true, nil, nil, nil},
+		{"Wrapper<UserDto>", []string{"Wrapper<UserDto>"}, true, nil, nil, nil},
+		{"Wrapper<object>", []string{"Wrapper<object>"}, true, nil, nil, nil},
+
+		{"TreeNode<UserDto>", []string{"TreeNode<UserDto>"}, false, []string{"UserDto", "ErrorDto", "TreeNode<UserDto>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"value", "children"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"UserDto", "TreeNode<UserDto>[]"}}},
+		{"TreeNode<TreeNode<UserDto>>", []string{"TreeNode<TreeNode<UserDto>>"}, false, []string{"UserDto", "ErrorDto", "TreeNode<UserDto>", "TreeNode<TreeNode<UserDto>>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"value", "children"}, {"value", "children"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"UserDto", "TreeNode<UserDto>[]"}, {"TreeNode<UserDto>", "TreeNode<TreeNode<UserDto>>[]"}}},
+		{"Comment<UserDto> | Thread<UserDto>", []string{"Comment<UserDto>", "Thread<UserDto>"}, false, []string{"UserDto", "ErrorDto", "Thread<UserDto>", "Comment<UserDto>"},
+			[][]string{{"uid", "name"}, {"type", "detail"}, {"comments"}, {"data", "thread"}},
+			[][]string{{"integer", "string"}, {"string", "string"}, {"Comment<UserDto>[]"}, {"UserDto", "Thread<UserDto>"}}},
+		{"Nested<UserDto>", []string{"Nested<UserDto>"}, true, nil, nil, nil},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			testPanic(t, tc.wantPanic, func() {
-				newDefinitions := prehandleDefinitionList(prehandledDefinitions, tc.giveTypes)
-				newObjectNames := make([]string, 0, len(newDefinitions))
-				for _, def := range newDefinitions {
-					newObjectNames = append(newObjectNames, def.name)
-				}
-				testMatchElements(t, newObjectNames, tc.wantObjectNames, "newObjectNames", "wantObjectNames")
-				for idx, def := range newDefinitions {
-					newPropNames := make([]string, 0, len(def.properties))
-					newPropTypes := make([]string, 0, len(def.properties))
-					for _, prop := range def.properties {
-						newPropNames = append(newPropNames, prop.name)
-						newPropTypes = append(newPropTypes, prop.typ)
-					}
-					testMatchElements(t, newPropNames, tc.wantPropNames[idx], "newPropNames", "wantPropNames")
-					testMatchElements(t, newPropTypes, tc.wantPropTypes[idx], "newPropTypes", "wantPropTypes")
+			newDefinitions, err := prehandleDefinitionList(prehandledDefinitions, tc.giveTypes, nil)
+			testError(t, tc.wantErr, err, "prehandleDefinitionList")
+			if tc.wantErr {
+				return
+			}
+			newObjectNames := make([]string, 0, len(newDefinitions))
+			for _, def := range newDefinitions {
+				newObjectNames = append(newObjectNames, def.name)
+			}
+			testMatchElements(t, newObjectNames, tc.wantObjectNames, "newObjectNames", "wantObjectNames")
+			for idx, def := range newDefinitions {
+				newPropNames := make([]string, 0, len(def.properties))
+				newPropTypes := make([]string, 0, len(def.properties))
+				for _, prop := range def.properties {
+					newPropNames = append(newPropNames, prop.name)
+					newPropTypes = append(newPropTypes, prop.typ)
 				}
-			}, "prehandleDefinitionList")
+				testMatchElements(t, newPropNames, tc.wantPropNames[idx], "newPropNames", "wantPropNames")
+				testMatchElements(t, newPropTypes, tc.wantPropTypes[idx], "newPropTypes", "wantPropTypes")
+			}
+		})
+	}
+}
+
+func TestGenericNaming(t *testing.T) {
+	for _, tc := range []struct {
+		give           string
+		wantOf         string
+		wantUnderscore string
+	}{
+		{"integer", "integer", "integer"},
+		{"UserDto[]", "UserDto[]", "UserDto[]"},
+		{"_Result<UserDto>", "ResultOfUserDto", "_Result_UserDto_"},
+		{"_Result<_Page<UserDto>>", "ResultOfPageOfUserDto", "_Result_Page_UserDto_"},
+		{"_Result<_Page<UserDto>>[]", "ResultOfPageOfUserDto[]", "_Result_Page_UserDto_[]"},
+		{"Pair<UserDto[], integer#int64>", "PairOfUserDtoArrayAndinteger_int64", "Pair_UserDtoArray_integer_int64_"},
+		{"map<string, _Result<UserDto>>", "map<string, ResultOfUserDto>", "map<string, _Result_UserDto_>"},
+		{"_Result<A|B>", "ResultOfoneOf_A_B", "_Result_oneOf_A_B_"},
+		{"A|_Result<B>", "oneOf<A, ResultOfB>", "oneOf<A, _Result_B_>"},
+		{"Invalid<", "Invalid<", "Invalid<"},
+	} {
+		t.Run(tc.give, func(t *testing.T) {
+			if got := nameTypeString(tc.give, nil); got != tc.give {
+				failNow(t, "nameTypeString with nil naming should keep the type, but got "+got)
+			}
+			if got := nameTypeString(tc.give, OfGenericNaming); got != tc.wantOf {
+				failNow(t, "nameTypeString with OfGenericNaming get "+got+", want "+tc.wantOf)
+			}
+			if got := nameTypeString(tc.give, UnderscoreGenericNaming); got != tc.wantUnderscore {
+				failNow(t, "nameTypeString with UnderscoreGenericNaming get "+got+", want "+tc.wantUnderscore)
+			}
 		})
 	}
 }
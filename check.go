@@ -0,0 +1,710 @@
+package goapidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// =========
+// valueRule
+// =========
+
+// valueRule represents the constraints of a value, which are shared by Param, Property and ItemOption.
+type valueRule struct {
+	pattern      string
+	enum         []interface{}
+	minLength    *int
+	maxLength    *int
+	minItems     *int
+	maxItems     *int
+	uniqueItems  bool
+	minimum      *float64
+	maximum      *float64
+	exclusiveMin bool
+	exclusiveMax bool
+	multipleOf   float64
+	itemOption   *ItemOption
+	nullable     bool
+}
+
+func ruleOfParam(p *Param) *valueRule {
+	return &valueRule{
+		pattern: p.pattern, enum: p.enum, minLength: p.minLength, maxLength: p.maxLength, minItems: p.minItems, maxItems: p.maxItems,
+		uniqueItems: p.uniqueItems, minimum: p.minimum, maximum: p.maximum, exclusiveMin: p.exclusiveMin, exclusiveMax: p.exclusiveMax,
+		multipleOf: p.multipleOf, itemOption: p.itemOption,
+	}
+}
+
+func ruleOfProperty(p *Property) *valueRule {
+	return &valueRule{
+		pattern: p.pattern, enum: p.enum, minLength: p.minLength, maxLength: p.maxLength, minItems: p.minItems, maxItems: p.maxItems,
+		uniqueItems: p.uniqueItems, minimum: p.minimum, maximum: p.maximum, exclusiveMin: p.exclusiveMin, exclusiveMax: p.exclusiveMax,
+		multipleOf: p.multipleOf, itemOption: p.itemOption, nullable: p.nullable,
+	}
+}
+
+func ruleOfItemOption(o *ItemOption) *valueRule {
+	if o == nil {
+		return &valueRule{}
+	}
+	return &valueRule{
+		pattern: o.pattern, enum: o.enum, minLength: o.minLength, maxLength: o.maxLength, minItems: o.minItems, maxItems: o.maxItems,
+		uniqueItems: o.uniqueItems, minimum: o.minimum, maximum: o.maximum, exclusiveMin: o.exclusiveMin, exclusiveMax: o.exclusiveMax,
+		multipleOf: o.multipleOf, itemOption: o.itemOption,
+	}
+}
+
+// ============
+// valueChecker
+// ============
+
+// ValueIssue represents a problem found when checking a request or response value against Document, such as a missing required param
+// or a property which does not match its type.
+type ValueIssue struct {
+	Location string `json:"location"` // such as query.page, body.users[0].name
+	Message  string `json:"message"`  // issue message
+}
+
+// String returns the formatted issue with location.
+func (v *ValueIssue) String() string {
+	return v.Location + ": " + v.Message
+}
+
+// valueChecker represents a checker which checks the decoded json values against the types of Document, and collects all the ValueIssue-s.
+type valueChecker struct {
+	defMap  map[string]*Definition // prehandled definitions, including the specialized generic ones
+	naming  GenericNamingFunc
+	request bool // readOnly properties are not required in request, and writeOnly properties are not required in response
+	issues  []*ValueIssue
+}
+
+// newValueChecker prehandles all the definitions of given Document, and creates a valueChecker.
+func newValueChecker(doc *Document, request bool) (*valueChecker, error) {
+	definitions, err := prehandleAllDefinitions(doc)
+	if err != nil {
+		return nil, err
+	}
+	defMap := make(map[string]*Definition, len(definitions))
+	for _, def := range definitions {
+		defMap[def.name] = def
+	}
+	return &valueChecker{defMap: defMap, naming: genericNamingOf(doc), request: request}, nil
+}
+
+// fork returns an empty valueChecker with the same definitions, which is used to check a value without reporting.
+func (c *valueChecker) fork() *valueChecker {
+	return &valueChecker{defMap: c.defMap, naming: c.naming, request: c.request}
+}
+
+func (c *valueChecker) report(loc, msg string) {
+	c.issues = append(c.issues, &ValueIssue{Location: loc, Message: msg})
+}
+
+// checkValue checks the decoded json value with given type, rule and inline object.
+func (c *valueChecker) checkValue(loc string, value interface{}, typ string, rule *valueRule, inline *InlineObject) {
+	if inline != nil {
+		c.checkInlineObject(loc, value, inlineObjectDepth(typ), rule, inline.properties)
+		return
+	}
+	at, err := parseApiType(typ)
+	if err != nil {
+		c.report(loc, err.Error())
+		return
+	}
+	c.checkType(loc, value, at, rule)
+}
+
+func (c *valueChecker) checkType(loc string, value interface{}, at *apiType, rule *valueRule) {
+	if value == nil {
+		if !rule.nullable {
+			c.report(loc, "Value must not be null")
+		}
+		return
+	}
+	switch at.kind {
+	case apiPrimeKind:
+		c.checkPrime(loc, value, at.prime, rule)
+	case apiArrayKind:
+		arr, ok := value.([]interface{})
+		if !ok {
+			c.report(loc, "Value must be an array")
+			return
+		}
+		c.checkItems(loc, arr, rule)
+		itemRule := ruleOfItemOption(rule.itemOption)
+		for idx, item := range arr {
+			c.checkType(loc+"["+strconv.Itoa(idx)+"]", item, at.array.item, itemRule)
+		}
+	case apiMapKind:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			c.report(loc, "Value must be an object")
+			return
+		}
+		for _, key := range sortedKeys(obj) {
+			if keyTyp := at.mapp.key.prime.typ; keyTyp != STRING {
+				if _, ok := parsePrimeValue(key, keyTyp).(string); ok {
+					c.report(loc+"."+key, "Key `"+key+"` must be "+articleOf(keyTyp)+" "+keyTyp)
+				}
+			}
+			c.checkType(loc+"."+key, obj[key], at.mapp.value, &valueRule{})
+		}
+	case apiUnionKind:
+		matched := 0
+		for _, item := range at.union.items {
+			sub := c.fork()
+			sub.checkType(loc, value, item, &valueRule{})
+			if len(sub.issues) == 0 {
+				matched++
+			}
+		}
+		if at.union.typ == ANYOF && matched == 0 {
+			c.report(loc, "Value must match at least one of `"+at.name+"`")
+		} else if at.union.typ == ONEOF && matched != 1 {
+			c.report(loc, "Value must match exactly one of `"+at.name+"`")
+		}
+	case apiObjectKind:
+		c.checkObject(loc, value, at, rule)
+	}
+}
+
+// checkObject checks the value with the definition of given object type, enum definition is checked as a primitive value, and the
+// subtype is chosen by discriminator if it exists.
+func (c *valueChecker) checkObject(loc string, value interface{}, at *apiType, rule *valueRule) {
+	def, ok := c.defMap[c.definitionName(at)]
+	if !ok {
+		c.report(loc, "Object type `"+at.name+"` not found")
+		return
+	}
+	if def.enumType != "" {
+		enumAt, err := parseApiType(def.enumType)
+		if err != nil {
+			c.report(loc, err.Error())
+			return
+		}
+		enumRule := *rule
+		enumRule.enum = def.enums
+		c.checkType(loc, value, enumAt, &enumRule)
+		return
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		c.report(loc, "Value must be an object")
+		return
+	}
+	if def.discriminator != "" {
+		if s, ok := obj[def.discriminator].(string); ok {
+			subName := s
+			if mapped, ok := def.mapping[s]; ok {
+				subName = mapped
+			}
+			if sub, ok := c.defMap[subName]; ok && sub.enumType == "" {
+				def = sub
+			}
+		}
+	}
+	c.checkDefinition(loc, obj, def, make(map[string]bool))
+}
+
+// checkDefinition checks the object value with the properties of given definition and the definitions it extends, visited is used to stop
+// the extending loop.
+func (c *valueChecker) checkDefinition(loc string, obj map[string]interface{}, def *Definition, visited map[string]bool) {
+	if visited[def.name] {
+		return // extending loop
+	}
+	visited[def.name] = true
+	for _, ext := range def.extends {
+		at, err := parseApiType(ext)
+		if err != nil {
+			continue
+		}
+		if extDef, ok := c.defMap[c.definitionName(at)]; ok {
+			c.checkDefinition(loc, obj, extDef, visited)
+		}
+	}
+	c.checkProperties(loc, obj, def.properties)
+}
+
+func (c *valueChecker) checkProperties(loc string, obj map[string]interface{}, properties []*Property) {
+	for _, prop := range properties {
+		propLoc := loc + "." + prop.name
+		value, ok := obj[prop.name]
+		if !ok {
+			if prop.required && !(c.request && prop.readOnly) && !(!c.request && prop.writeOnly) {
+				c.report(propLoc, "Required property is missing")
+			}
+			continue
+		}
+		c.checkValue(propLoc, value, prop.typ, ruleOfProperty(prop), prop.inlineObject)
+	}
+}
+
+// checkInlineObject checks the value with given inline object properties, arrayDepth is the array depth of inline object type.
+func (c *valueChecker) checkInlineObject(loc string, value interface{}, arrayDepth int, rule *valueRule, properties []*Property) {
+	if value == nil {
+		if !rule.nullable {
+			c.report(loc, "Value must not be null")
+		}
+		return
+	}
+	if arrayDepth > 0 {
+		arr, ok := value.([]interface{})
+		if !ok {
+			c.report(loc, "Value must be an array")
+			return
+		}
+		c.checkItems(loc, arr, rule)
+		itemRule := ruleOfItemOption(rule.itemOption)
+		for idx, item := range arr {
+			c.checkInlineObject(loc+"["+strconv.Itoa(idx)+"]", item, arrayDepth-1, itemRule, properties)
+		}
+		return
+	}
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		c.report(loc, "Value must be an object")
+		return
+	}
+	c.checkProperties(loc, obj, properties)
+}
+
+// definitionName returns the name of prehandled definition of given object type.
+func (c *valueChecker) definitionName(at *apiType) string {
+	return definitionNameOf(at, c.naming)
+}
+
+// definitionNameOf returns the name of prehandled definition of given object type, that is the specialized generic definition name
+// named by the naming strategy.
+func definitionNameOf(at *apiType, naming GenericNamingFunc) string {
+	name := at.object.typ
+	if len(at.object.generics) > 0 {
+		args := make([]string, 0, len(at.object.generics))
+		for _, arg := range at.object.generics {
+			args = append(args, arg.name)
+		}
+		name += "<" + strings.Join(args, ", ") + ">"
+	}
+	return nameTypeString(name, naming)
+}
+
+// paramValue converts the string values of a non-body param to the json value of given type.
+func (c *valueChecker) paramValue(values []string, at *apiType, rule *valueRule, collectionFormat string) interface{} {
+	if at.kind != apiArrayKind {
+		return c.primeValue(values[0], at)
+	}
+	items := values
+	if collectionFormat != MULTI {
+		items = splitCollection(values[0], collectionFormat)
+	}
+	itemOption := rule.itemOption
+	if itemOption == nil {
+		itemOption = &ItemOption{}
+	}
+	out := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		out = append(out, c.paramValue([]string{item}, at.array.item, ruleOfItemOption(itemOption), itemOption.collectionFormat))
+	}
+	return out
+}
+
+// primeValue converts the string value to the json value of given primitive or enum type, returns the string itself if failed.
+func (c *valueChecker) primeValue(s string, at *apiType) interface{} {
+	typ := ""
+	if at.kind == apiPrimeKind {
+		typ = at.prime.typ
+	} else if at.kind == apiObjectKind {
+		if def, ok := c.defMap[nameTypeString(at.name, c.naming)]; ok {
+			typ = strings.SplitN(def.enumType, "#", 2)[0]
+		}
+	}
+	switch typ {
+	case INTEGER, NUMBER:
+		return json.Number(s)
+	case BOOLEAN:
+		return parsePrimeValue(s, BOOLEAN)
+	}
+	return s
+}
+
+// splitCollection splits the array param value by given collection format, csv is used by default.
+func splitCollection(s, collectionFormat string) []string {
+	sep := ","
+	switch collectionFormat {
+	case SSV:
+		sep = " "
+	case TSV:
+		sep = "\t"
+	case PIPES:
+		sep = "|"
+	}
+	return strings.Split(s, sep)
+}
+
+// ============
+// prime checks
+// ============
+
+func (c *valueChecker) checkPrime(loc string, value interface{}, prime *apiPrime, rule *valueRule) {
+	switch prime.typ {
+	case FILE:
+		return
+	case OBJECT:
+		if _, ok := value.(map[string]interface{}); !ok {
+			c.report(loc, "Value must be an object")
+		}
+		return
+	case STRING:
+		s, ok := value.(string)
+		if !ok {
+			c.report(loc, "Value must be a string")
+			return
+		}
+		c.checkFormat(loc, s, prime)
+		c.checkString(loc, s, rule)
+	case INTEGER, NUMBER:
+		n, ok := value.(json.Number)
+		if !ok {
+			c.report(loc, "Value must be "+articleOf(prime.typ)+" "+prime.typ)
+			return
+		}
+		f, err := n.Float64()
+		if err != nil || (prime.typ == INTEGER && f != math.Trunc(f)) {
+			c.report(loc, "Value must be "+articleOf(prime.typ)+" "+prime.typ)
+			return
+		}
+		c.checkFormat(loc, n.String(), prime)
+		c.checkNumber(loc, f, rule)
+	case BOOLEAN:
+		if _, ok := value.(bool); !ok {
+			c.report(loc, "Value must be a boolean")
+			return
+		}
+	}
+	if len(rule.enum) > 0 && !enumContains(rule.enum, value) {
+		c.report(loc, fmt.Sprintf("Value must be one of %v", rule.enum))
+	}
+}
+
+func (c *valueChecker) checkFormat(loc, value string, prime *apiPrime) {
+	if f := GetFormat(prime.format, prime.typ); f != nil && !f.Match(value) {
+		c.report(loc, "Value `"+value+"` does not match format `"+prime.format+"`")
+	}
+}
+
+func (c *valueChecker) checkString(loc, s string, rule *valueRule) {
+	length := utf8.RuneCountInString(s)
+	if rule.minLength != nil && length < *rule.minLength {
+		c.report(loc, "Value length must be greater than or equal to "+strconv.Itoa(*rule.minLength))
+	}
+	if rule.maxLength != nil && length > *rule.maxLength {
+		c.report(loc, "Value length must be less than or equal to "+strconv.Itoa(*rule.maxLength))
+	}
+	if rule.pattern != "" {
+		if re := compilePattern(rule.pattern); re != nil && !re.MatchString(s) {
+			c.report(loc, "Value `"+s+"` does not match pattern `"+rule.pattern+"`")
+		}
+	}
+}
+
+func (c *valueChecker) checkNumber(loc string, f float64, rule *valueRule) {
+	if rule.minimum != nil {
+		if rule.exclusiveMin && f <= *rule.minimum {
+			c.report(loc, "Value must be greater than "+formatFloat(*rule.minimum))
+		} else if f < *rule.minimum {
+			c.report(loc, "Value must be greater than or equal to "+formatFloat(*rule.minimum))
+		}
+	}
+	if rule.maximum != nil {
+		if rule.exclusiveMax && f >= *rule.maximum {
+			c.report(loc, "Value must be less than "+formatFloat(*rule.maximum))
+		} else if f > *rule.maximum {
+			c.report(loc, "Value must be less than or equal to "+formatFloat(*rule.maximum))
+		}
+	}
+	if rule.multipleOf > 0 {
+		if q := f / rule.multipleOf; q != math.Trunc(q) {
+			c.report(loc, "Value must be a multiple of "+formatFloat(rule.multipleOf))
+		}
+	}
+}
+
+func (c *valueChecker) checkItems(loc string, arr []interface{}, rule *valueRule) {
+	if rule.minItems != nil && len(arr) < *rule.minItems {
+		c.report(loc, "Array length must be greater than or equal to "+strconv.Itoa(*rule.minItems))
+	}
+	if rule.maxItems != nil && len(arr) > *rule.maxItems {
+		c.report(loc, "Array length must be less than or equal to "+strconv.Itoa(*rule.maxItems))
+	}
+	if rule.uniqueItems {
+		seen := make(map[string]bool, len(arr))
+		for _, item := range arr {
+			key := fmt.Sprintf("%#v", item)
+			if seen[key] {
+				c.report(loc, "Array items must be unique")
+				break
+			}
+			seen[key] = true
+		}
+	}
+}
+
+// =============
+// CheckResponse
+// =============
+
+// ResponseCheckError represents the error returned by ResponseChecker.CheckResponse, which contains all the issues found in the response.
+type ResponseCheckError struct {
+	Method string        // method of the operation
+	Route  string        // route of the operation
+	Code   int           // status code of the response
+	Issues []*ValueIssue // issues found in the response
+}
+
+// Error returns the formatted error message with all the issues.
+func (e *ResponseCheckError) Error() string {
+	issues := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		issues = append(issues, issue.String())
+	}
+	return fmt.Sprintf("response `%d` of operation `%s %s` does not conform to the document: %s", e.Code, e.Method, e.Route, strings.Join(issues, "; "))
+}
+
+// ResponseChecker checks the http responses against the operations of Document, which is created by NewResponseChecker.
+type ResponseChecker struct {
+	validator *requestValidator
+	basePath  string
+}
+
+// NewResponseChecker prehandles given Document and returns a ResponseChecker, which is designed for the httptest suites to keep the Document
+// and handlers consistent. DocumentError is returned if the Document is invalid, and the Document should not be modified after that.
+func NewResponseChecker(doc *Document) (*ResponseChecker, error) {
+	validator, err := newRequestValidator(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseChecker{validator: validator, basePath: strings.TrimSuffix(doc.basePath, "/")}, nil
+}
+
+// CheckResponse finds the Operation by method and route, the route can be the declared one (such as `/user/{id}`) or a concrete path
+// relative to the base path (such as `/user/1`). Then it checks that the response status code is declared in the operation, and the
+// declared headers and json body conform to their types, returns ResponseCheckError if not. Notes that the response body is read and
+// restored, and the body larger than 32 MB is not checked.
+func (rc *ResponseChecker) CheckResponse(method, route string, resp *http.Response) error {
+	var op *Operation
+	for _, r := range rc.validator.routes {
+		if strings.EqualFold(r.operation.method, method) && r.operation.route == route {
+			op = r.operation
+			break
+		}
+	}
+	if op == nil {
+		if u, err := url.Parse(rc.basePath + route); err == nil {
+			if r, _ := rc.validator.match(&http.Request{Method: method, URL: u}); r != nil {
+				op = r.operation
+			}
+		}
+	}
+	if op == nil {
+		return newDocumentError("Operation `" + strings.ToUpper(method) + " " + route + "` not found")
+	}
+
+	c := &valueChecker{defMap: rc.validator.defMap, naming: rc.validator.naming, request: false}
+	c.checkResponse(op, resp)
+	if len(c.issues) == 0 {
+		return nil
+	}
+	return &ResponseCheckError{Method: strings.ToUpper(op.method), Route: op.route, Code: resp.StatusCode, Issues: c.issues}
+}
+
+// CheckResponse creates a ResponseChecker and checks the response against the operation found by method and route, see
+// ResponseChecker.CheckResponse for details. Notes that the Document is prehandled in every call, so use NewResponseChecker instead to
+// check lots of responses.
+func (d *Document) CheckResponse(method, route string, resp *http.Response) error {
+	checker, err := NewResponseChecker(d)
+	if err != nil {
+		return err
+	}
+	return checker.CheckResponse(method, route, resp)
+}
+
+// CheckResponse creates a ResponseChecker of global Document, and checks the response against the operation found by method and route.
+func CheckResponse(method, route string, resp *http.Response) error {
+	return _document.CheckResponse(method, route, resp)
+}
+
+func (c *valueChecker) checkResponse(op *Operation, resp *http.Response) {
+	var response *Response
+	for _, r := range op.responses {
+		if r.code == resp.StatusCode {
+			response = r
+			break
+		}
+	}
+	if response == nil {
+		c.report("status", "Response code `"+strconv.Itoa(resp.StatusCode)+"` is not declared")
+		return
+	}
+
+	for _, h := range response.headers {
+		values := resp.Header[http.CanonicalHeaderKey(h.name)]
+		if len(values) == 0 || values[0] == "" {
+			continue
+		}
+		at, err := parseApiType(h.typ)
+		if err != nil {
+			c.report(HEADER+"."+h.name, err.Error())
+			continue
+		}
+		c.checkType(HEADER+"."+h.name, c.paramValue(values, at, &valueRule{}, ""), at, &valueRule{})
+	}
+	if response.typ == "" && response.inlineObject == nil {
+		return
+	}
+
+	// read and restore response body, the body larger than 32 MB is not checked
+	if !isJsonContentType(resp.Header.Get("Content-Type")) {
+		return
+	}
+	var body []byte
+	if resp.Body != nil && resp.Body != http.NoBody {
+		var complete bool
+		var err error
+		body, resp.Body, complete, err = readLimitedBody(resp.Body)
+		if err != nil {
+			c.report(BODY, "Response body cannot be read")
+			return
+		}
+		if !complete {
+			return
+		}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		c.report(BODY, "Response body is missing")
+		return
+	}
+	value, err := decodeJson(body)
+	if err != nil {
+		c.report(BODY, "Response body is not a valid json")
+		return
+	}
+	c.checkValue(BODY, value, response.typ, &valueRule{}, response.inlineObject)
+}
+
+// =======
+// helpers
+// =======
+
+// isJsonContentType checks whether given content type is json, such as `application/json` and `application/problem+json`, and the empty
+// content type is regarded as json.
+func isJsonContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == JSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// decodeJson decodes given json bytes, and keeps the numbers as json.Number.
+func decodeJson(bs []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+// _patterns caches the compiled patterns of value rules, invalid patterns are cached as nil.
+var _patterns sync.Map
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := _patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	_patterns.Store(pattern, re)
+	return re
+}
+
+// enumContains checks whether given value is one of the enum values, numbers are compared by their float values.
+func enumContains(enums []interface{}, value interface{}) bool {
+	for _, enum := range enums {
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				if g, ok := floatOf(enum); ok && f == g {
+					return true
+				}
+			}
+		}
+		if fmt.Sprint(enum) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func floatOf(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func articleOf(typ string) string {
+	if typ == INTEGER {
+		return "an"
+	}
+	return "a"
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
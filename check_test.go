@@ -0,0 +1,141 @@
+package goapidoc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	doc := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).
+		Definitions(
+			NewDefinition("_Result", "result").Generics("T").Properties(
+				NewProperty("code", "integer#int32", true, "code"),
+				NewProperty("message", "string", true, "message"),
+				NewProperty("data", "T", true, "data"),
+			),
+			NewDefinition("_Page", "page").Generics("T").Properties(
+				NewProperty("page", "integer#int32", true, "page").Minimum(1),
+				NewProperty("total", "integer#int32", true, "total"),
+				NewProperty("data", "T[]", true, "data"),
+			),
+			NewDefinition("UserDto", "user").Properties(
+				NewProperty("uid", "integer#int64", true, "uid"),
+				NewProperty("username", "string", true, "username"),
+				NewProperty("password", "string", true, "password").WriteOnly(true),
+				NewProperty("birthday", "string#date?", false, "birthday"),
+			),
+			NewDefinition("Pet", "pet").Discriminator("kind", map[string]string{"cat": "Cat"}).Properties(
+				NewProperty("kind", "string", true, "kind"),
+			),
+			NewDefinition("Cat", "cat").Extends("Pet").Properties(
+				NewProperty("lives", "integer", true, "lives"),
+			),
+		).
+		Operations(
+			NewGetOperation("/users", "query users").
+				Responses(
+					NewResponse(200, "_Result<_Page<UserDto>>").Headers(NewResponseHeader("X-Total", "integer", "total")),
+					NewResponse(204, ""),
+				),
+			NewGetOperation("/pet", "get pet").
+				Responses(NewResponse(200, "Pet")),
+			NewGetOperation("/text", "get text").
+				Responses(NewResponse(200, "string")),
+			NewGetOperation("/user/{id}", "get user").
+				Params(NewPathParam("id", "integer#int64", true, "id")).
+				Responses(NewResponse(200, "UserDto")),
+			NewGetOperation("/user/me", "get me").
+				Responses(NewResponse(200, "string")),
+		)
+
+	checker, err := NewResponseChecker(doc)
+	if err != nil {
+		failNow(t, "NewResponseChecker failed: "+err.Error())
+	}
+
+	for _, tc := range []struct {
+		giveRoute  string
+		giveCode   int
+		giveHeader map[string]string
+		giveBody   string
+		wantIssues []string
+	}{
+		{"/users", 200, nil, `{"code": 200, "message": "success", "data": {"page": 1, "total": 1, "data": [{"uid": 1, "username": "a", "birthday": null}]}}`, nil},
+		{"/users", 200, map[string]string{"X-Total": "1"}, `{"code": 200, "message": "success", "data": {"page": 1, "total": 0, "data": []}}`, nil},
+		{"/users", 200, map[string]string{"X-Total": "x"}, `{"code": 200, "message": "success", "data": {"page": 1, "total": 0, "data": []}}`, []string{"header.X-Total"}},
+		{"/users", 200, nil, `{"code": 200, "message": "success", "data": {"page": 0, "total": 1, "data": [{"uid": "1", "birthday": "x"}]}}`,
+			[]string{"body.data.page", "body.data.data[0].uid", "body.data.data[0].username", "body.data.data[0].birthday"}},
+		{"/users", 200, nil, `{"code": 200, "data": null}`, []string{"body.message", "body.data"}},
+		{"/users", 200, nil, ``, []string{"body"}},
+		{"/users", 200, nil, `{`, []string{"body"}},
+		{"/users", 204, nil, ``, nil},
+		{"/users", 404, nil, `{}`, []string{"status"}},
+		{"/pet", 200, nil, `{"kind": "dog"}`, nil},
+		{"/pet", 200, nil, `{"kind": "cat", "lives": 9}`, nil},
+		{"/pet", 200, nil, `{"kind": "cat"}`, []string{"body.lives"}},
+		{"/pet", 200, nil, `{}`, []string{"body.kind"}},
+		{"/text", 200, map[string]string{"Content-Type": "text/plain"}, `text`, nil},
+		{"/user/{id}", 200, nil, `{"uid": 1, "username": "a"}`, nil},
+		{"/user/1", 200, nil, `{"uid": 1, "username": "a"}`, nil},
+		{"/user/1", 200, nil, `"me"`, []string{"body"}},
+		{"/user/me", 200, nil, `"me"`, nil},
+	} {
+		t.Run(tc.giveRoute+" "+tc.giveBody, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			for k, v := range tc.giveHeader {
+				rec.Header().Set(k, v)
+			}
+			rec.WriteHeader(tc.giveCode)
+			_, _ = rec.WriteString(tc.giveBody)
+			resp := rec.Result()
+
+			err := checker.CheckResponse("get", tc.giveRoute, resp)
+			testError(t, len(tc.wantIssues) > 0, err, "CheckResponse")
+			if err == nil {
+				return
+			}
+			checkErr, ok := err.(*ResponseCheckError)
+			if !ok {
+				failNow(t, "CheckResponse returns an error which is not ResponseCheckError: "+err.Error())
+			}
+			locations := make([]string, 0, len(checkErr.Issues))
+			for _, issue := range checkErr.Issues {
+				locations = append(locations, issue.Location)
+			}
+			testMatchElements(t, locations, tc.wantIssues, "issue locations", "wanted locations")
+		})
+	}
+
+	t.Run("Restore body", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_, _ = rec.WriteString(`{"kind": "dog"}`)
+		resp := rec.Result()
+		testError(t, false, checker.CheckResponse(GET, "/pet", resp), "CheckResponse")
+		bs, _ := ioutil.ReadAll(resp.Body)
+		if string(bs) != `{"kind": "dog"}` {
+			failNow(t, "CheckResponse does not restore the response body")
+		}
+	})
+
+	t.Run("Operation not found", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+		testError(t, true, checker.CheckResponse(POST, "/users", resp), "CheckResponse")
+		testError(t, true, checker.CheckResponse(GET, "/users/{id}", resp), "CheckResponse")
+		testError(t, true, checker.CheckResponse(GET, "/user/1/2", resp), "CheckResponse")
+		testError(t, true, doc.CheckResponse(POST, "/user/1", resp), "CheckResponse")
+	})
+
+	t.Run("Invalid document", func(t *testing.T) {
+		d := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "Unknown")))
+		checker, err := NewResponseChecker(d)
+		testError(t, true, err, "NewResponseChecker")
+		if checker != nil {
+			failNow(t, "NewResponseChecker returns a checker for invalid document")
+		}
+		resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+		testError(t, true, d.CheckResponse(GET, "/", resp), "CheckResponse")
+	})
+}
@@ -24,6 +24,14 @@ const (
 	ARRAY   = "array"   // ARRAY type: array
 	FILE    = "file"    // FILE type: file
 	OBJECT  = "object"  // OBJECT type: object
+	MAP     = "map"     // MAP type: map<K, V>, object with additional properties
+	ONEOF   = "oneOf"   // ONEOF type: oneOf<A, B> or A|B, exactly one of the item types
+	ANYOF   = "anyOf"   // ANYOF type: anyOf<A, B>, one or more of the item types
+)
+
+// generic constraint
+const (
+	PRIMITIVE = "primitive" // PRIMITIVE generic constraint: T: primitive, only primitive and enum types, and OBJECT is for object types
 )
 
 // format
@@ -37,6 +45,13 @@ const (
 	DATE     = "date"      // DATE format: As defined by full-date - RFC3339
 	DATETIME = "date-time" // DATETIME format: As defined by date-time - RFC3339
 	PASSWORD = "password"  // PASSWORD format: Used to hint UIs the input needs to be obscured
+	EMAIL    = "email"     // EMAIL format: email address
+	UUID     = "uuid"      // UUID format: universally unique identifier
+	URI      = "uri"       // URI format: uniform resource identifier - RFC3986
+	IPV4     = "ipv4"      // IPV4 format: IPv4 address in dot-decimal notation
+	IPV6     = "ipv6"      // IPV6 format: IPv6 address
+	HOSTNAME = "hostname"  // HOSTNAME format: internet host name - RFC1123
+	DECIMAL  = "decimal"   // DECIMAL format: decimal number in string, such as "-1.25"
 )
 
 // param
@@ -81,3 +96,21 @@ const (
 	PIPES = "pipes" // PIPES collection format: foo|bar
 	MULTI = "multi" // MULTI collection format: foo=bar&foo=baz
 )
+
+// handler ui
+const (
+	SWAGGER_UI          = "swagger-ui"          // SWAGGER_UI handler ui: bundled Swagger UI with try-it-out
+	SWAGGER_UI_READONLY = "swagger-ui-readonly" // SWAGGER_UI_READONLY handler ui: bundled Swagger UI without try-it-out
+	NO_UI               = "none"                // NO_UI handler ui: only serves the generated documents
+)
+
+// mock server
+const (
+	MOCK_STATUS_HEADER = "X-Mock-Status" // MOCK_STATUS_HEADER mock server request header: selects the declared response status code
+)
+
+// severity
+const (
+	SEVERITY_ERROR   = "error"   // SEVERITY_ERROR severity: the document cannot be generated
+	SEVERITY_WARNING = "warning" // SEVERITY_WARNING severity: the document can be generated, but may be not expected
+)
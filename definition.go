@@ -1,5 +1,9 @@
 package goapidoc
 
+import (
+	"strings"
+)
+
 // ==========
 // Definition
 // ==========
@@ -9,9 +13,18 @@ type Definition struct {
 	name string
 	desc string
 
-	xmlRepr    *XMLRepr
-	generics   []string
-	properties []*Property
+	xmlRepr       *XMLRepr
+	generics      []string
+	genericParams []*apiGeneric // only set in prehandled definition
+	extends       []string
+	discriminator string
+	mapping       map[string]string
+	properties    []*Property
+
+	enumType     string
+	enums        []interface{}
+	enumVarNames []string
+	enumDescs    []string
 }
 
 // NewDefinition creates a default Definition with given arguments.
@@ -19,6 +32,13 @@ func NewDefinition(name, desc string) *Definition {
 	return &Definition{name: name, desc: desc}
 }
 
+// NewEnumDefinition creates an enum Definition with given primitive type and enum values, such as `NewEnumDefinition("Gender", "string",
+// "Secret", "Male", "Female")`. Enum definitions can be used as type names anywhere, and are rendered as a schema with enum in Swagger
+// and OpenAPI3, and an enum data structure in API Blueprint.
+func NewEnumDefinition(name, typ string, values ...interface{}) *Definition {
+	return &Definition{name: name, enumType: typ, enums: values}
+}
+
 // GetName returns the name from Definition.
 func (d *Definition) GetName() string { return d.name }
 
@@ -31,9 +51,30 @@ func (d *Definition) GetXMLRepr() *XMLRepr { return d.xmlRepr }
 // GetGenerics returns the whole generics from Definition.
 func (d *Definition) GetGenerics() []string { return d.generics }
 
+// GetExtends returns the whole extended definitions from Definition.
+func (d *Definition) GetExtends() []string { return d.extends }
+
+// GetDiscriminator returns the discriminator property name from Definition.
+func (d *Definition) GetDiscriminator() string { return d.discriminator }
+
+// GetDiscriminatorMapping returns the discriminator mapping from Definition.
+func (d *Definition) GetDiscriminatorMapping() map[string]string { return d.mapping }
+
 // GetProperties returns the whole properties from Definition.
 func (d *Definition) GetProperties() []*Property { return d.properties }
 
+// GetEnumType returns the enum type from Definition, empty type means the definition is not an enum definition.
+func (d *Definition) GetEnumType() string { return d.enumType }
+
+// GetEnum returns the whole enum values from Definition.
+func (d *Definition) GetEnum() []interface{} { return d.enums }
+
+// GetEnumVarNames returns the whole enum variable names from Definition.
+func (d *Definition) GetEnumVarNames() []string { return d.enumVarNames }
+
+// GetEnumDescs returns the whole enum descriptions from Definition.
+func (d *Definition) GetEnumDescs() []string { return d.enumDescs }
+
 // Name sets the name in Definition.
 func (d *Definition) Name(name string) *Definition {
 	d.name = name
@@ -52,12 +93,30 @@ func (d *Definition) XMLRepr(repr *XMLRepr) *Definition {
 	return d
 }
 
-// Generics sets the whole generics in Definition.
+// Generics sets the whole generics in Definition. A generic parameter can declare a constraint and a default type, such as `T: object`,
+// `T: primitive` and `T = UserDto`. Trailing generic arguments with defaults can be omitted, and using a generic definition without any
+// argument, such as `_Result`, fills the parameters without default by free-form `object`.
 func (d *Definition) Generics(generics ...string) *Definition {
 	d.generics = generics
 	return d
 }
 
+// Extends sets the whole extended definitions in Definition, these definitions are rendered as allOf in Swagger and OpenAPI3, and Include
+// in API Blueprint. Notes that extended types must be object types, and generic types are also supported, such as `_Base<T>`.
+func (d *Definition) Extends(extends ...string) *Definition {
+	d.extends = extends
+	return d
+}
+
+// Discriminator sets the discriminator property name and mapping in Definition, the mapping is from the property value to the subtype
+// definition name, such as `"email": "EmailNotification"`. Notes that the discriminator property and the mapping are only supported in
+// Swagger and OpenAPI3, and the mapping is only supported in OpenAPI3, subtypes in Swagger are those definitions which extend this one.
+func (d *Definition) Discriminator(propertyName string, mapping map[string]string) *Definition {
+	d.discriminator = propertyName
+	d.mapping = mapping
+	return d
+}
+
 // Properties sets the whole properties in Definition.
 func (d *Definition) Properties(properties ...*Property) *Definition {
 	d.properties = properties
@@ -70,6 +129,32 @@ func (d *Definition) AddProperties(properties ...*Property) *Definition {
 	return d
 }
 
+// EnumType sets the enum type in Definition, this must be a primitive type, such as `string` or `integer#int32`.
+func (d *Definition) EnumType(typ string) *Definition {
+	d.enumType = typ
+	return d
+}
+
+// Enum sets the whole enum values in Definition.
+func (d *Definition) Enum(values ...interface{}) *Definition {
+	d.enums = values
+	return d
+}
+
+// EnumVarNames sets the whole enum variable names in Definition, these names are rendered as x-enum-varnames in Swagger and OpenAPI3,
+// and must be matched with the enum values one by one.
+func (d *Definition) EnumVarNames(names ...string) *Definition {
+	d.enumVarNames = names
+	return d
+}
+
+// EnumDescs sets the whole enum descriptions in Definition, these descriptions are rendered as x-enum-descriptions in Swagger and
+// OpenAPI3, and member descriptions in API Blueprint, and must be matched with the enum values one by one.
+func (d *Definition) EnumDescs(descs ...string) *Definition {
+	d.enumDescs = descs
+	return d
+}
+
 // =======
 // XMLRepr
 // =======
@@ -162,11 +247,18 @@ type Property struct {
 	multipleOf       float64
 	itemOption       *ItemOption
 	xmlRepr          *XMLRepr
+	inlineObject     *InlineObject
+
+	nullable  bool
+	readOnly  bool
+	writeOnly bool
 }
 
-// NewProperty creates a default Property with given arguments.
+// NewProperty creates a default Property with given arguments, a `?` suffix of the type means the property is nullable, such as
+// `string#date?`.
 func NewProperty(name, typ string, required bool, desc string) *Property {
-	return &Property{name: name, typ: typ, required: required, desc: desc}
+	p := &Property{name: name, required: required, desc: desc}
+	return p.Type(typ)
 }
 
 // GetName returns the name from Property.
@@ -235,6 +327,18 @@ func (p *Property) GetItemOption() *ItemOption { return p.itemOption }
 // GetXMLRepr returns the xml repr from Property.
 func (p *Property) GetXMLRepr() *XMLRepr { return p.xmlRepr }
 
+// GetInlineObject returns the inline object from Property.
+func (p *Property) GetInlineObject() *InlineObject { return p.inlineObject }
+
+// GetNullable returns the nullable from Property.
+func (p *Property) GetNullable() bool { return p.nullable }
+
+// GetReadOnly returns the readOnly from Property.
+func (p *Property) GetReadOnly() bool { return p.readOnly }
+
+// GetWriteOnly returns the writeOnly from Property.
+func (p *Property) GetWriteOnly() bool { return p.writeOnly }
+
 // Name sets the name in Property.
 func (p *Property) Name(name string) *Property {
 	p.name = name
@@ -243,6 +347,10 @@ func (p *Property) Name(name string) *Property {
 
 // Type sets the type in Property.
 func (p *Property) Type(typ string) *Property {
+	if t := strings.TrimSpace(typ); strings.HasSuffix(t, "?") {
+		p.typ, p.nullable = strings.TrimSpace(t[:len(t)-1]), true // xxx? -> xxx, nullable
+		return p
+	}
 	p.typ = typ
 	return p
 }
@@ -388,6 +496,32 @@ func (p *Property) XMLRepr(repr *XMLRepr) *Property {
 	return p
 }
 
+// InlineObject sets the inline object in Property, notes that the property type must be object or array of object, such as `object[]`.
+func (p *Property) InlineObject(inline *InlineObject) *Property {
+	p.inlineObject = inline
+	return p
+}
+
+// Nullable sets the nullable in Property, this is rendered as x-nullable in Swagger, nullable in OpenAPI3 and API Blueprint.
+func (p *Property) Nullable(nullable bool) *Property {
+	p.nullable = nullable
+	return p
+}
+
+// ReadOnly sets the readOnly in Property, which means the property is only sent in response, notes that this is not supported in API
+// Blueprint.
+func (p *Property) ReadOnly(readOnly bool) *Property {
+	p.readOnly = readOnly
+	return p
+}
+
+// WriteOnly sets the writeOnly in Property, which means the property is only sent in request, notes that this is only supported in
+// OpenAPI3.
+func (p *Property) WriteOnly(writeOnly bool) *Property {
+	p.writeOnly = writeOnly
+	return p
+}
+
 // ==========
 // ItemOption
 // ==========
@@ -602,6 +736,36 @@ func (o *ItemOption) XMLRepr(repr *XMLRepr) *ItemOption {
 	return o
 }
 
+// ============
+// InlineObject
+// ============
+
+// InlineObject represents an anonymous object type of Property, Param and Response, which is rendered inline instead of referring to a
+// Definition.
+type InlineObject struct {
+	properties []*Property
+}
+
+// NewInlineObject creates a default InlineObject with given properties.
+func NewInlineObject(properties ...*Property) *InlineObject {
+	return &InlineObject{properties: properties}
+}
+
+// GetProperties returns the whole properties from InlineObject.
+func (i *InlineObject) GetProperties() []*Property { return i.properties }
+
+// Properties sets the whole properties in InlineObject.
+func (i *InlineObject) Properties(properties ...*Property) *InlineObject {
+	i.properties = properties
+	return i
+}
+
+// AddProperties add some properties into InlineObject.
+func (i *InlineObject) AddProperties(properties ...*Property) *InlineObject {
+	i.properties = append(i.properties, properties...)
+	return i
+}
+
 // ========
 // cloneXXX
 // ========
@@ -658,5 +822,21 @@ func cloneProperty(p *Property) *Property {
 		multipleOf:       p.multipleOf,
 		itemOption:       cloneItemOption(p.itemOption),
 		xmlRepr:          p.xmlRepr,
+		inlineObject:     cloneInlineObject(p.inlineObject),
+		nullable:         p.nullable,
+		readOnly:         p.readOnly,
+		writeOnly:        p.writeOnly,
+	}
+}
+
+// cloneInlineObject clones the given InlineObject with all properties recursively.
+func cloneInlineObject(i *InlineObject) *InlineObject {
+	if i == nil {
+		return nil
+	}
+	out := &InlineObject{properties: make([]*Property, 0, len(i.properties))}
+	for _, p := range i.properties {
+		out.properties = append(out.properties, cloneProperty(p))
 	}
+	return out
 }
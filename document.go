@@ -262,6 +262,8 @@ type Option struct {
 	externalDoc   *ExternalDoc
 	additionalDoc string
 	routesOptions []*RoutesOption
+	genericNaming GenericNamingFunc
+	autoExample   bool
 }
 
 // NewOption creates a default Option.
@@ -296,6 +298,12 @@ func (o *Option) GetAdditionalDoc() string { return o.additionalDoc }
 // GetRoutesOptions returns the whole routes options from Option.
 func (o *Option) GetRoutesOptions() []*RoutesOption { return o.routesOptions }
 
+// GetGenericNaming returns the generic naming strategy from Option.
+func (o *Option) GetGenericNaming() GenericNamingFunc { return o.genericNaming }
+
+// GetAutoExample returns the auto example flag from Option.
+func (o *Option) GetAutoExample() bool { return o.autoExample }
+
 // Schemes sets the whole schemes in Option.
 func (o *Option) Schemes(schemes ...string) *Option {
 	o.schemes = schemes
@@ -356,7 +364,8 @@ func (o *Option) AddSecurities(securities ...*Security) *Option {
 	return o
 }
 
-// GlobalParams sets the whole global params in Option.
+// GlobalParams sets the whole global params in Option, notes that the global path params are only used by the routes with matching
+// placeholders.
 func (o *Option) GlobalParams(globalParams ...*Param) *Option {
 	o.globalParams = globalParams
 	return o
@@ -392,6 +401,22 @@ func (o *Option) AddRoutesOptions(options ...*RoutesOption) *Option {
 	return o
 }
 
+// GenericNaming sets the naming strategy of specialized generic definitions in Option, which is applied to definition names, $ref and
+// API Blueprint data structure names. Nil strategy keeps the names such as `_Result<_Page<UserDto>>` (with union arguments written as
+// `oneOf<Cat, Dog>`), and OfGenericNaming and UnderscoreGenericNaming can be used for those tools which reject these names.
+func (o *Option) GenericNaming(naming GenericNamingFunc) *Option {
+	o.genericNaming = naming
+	return o
+}
+
+// AutoExample sets the auto example flag in Option, the missing json request examples and response examples of swagger and openapi
+// documents will be filled by the examples synthesized from their types if it is true, see Document.ExampleFor. Notes that the body
+// sections of API Blueprint document are always filled.
+func (o *Option) AutoExample(autoExample bool) *Option {
+	o.autoExample = autoExample
+	return o
+}
+
 // ===
 // Tag
 // ===
@@ -110,7 +110,9 @@ func TestSetGet(t *testing.T) {
 				AdditionalDoc("This is endpoint /user")).
 			AddRoutesOptions(NewRoutesOption("/user/{id}").
 				Summary("Specific user").
-				AdditionalDoc("This is endpoint /user/{id}")))
+				AdditionalDoc("This is endpoint /user/{id}")).
+			GenericNaming(OfGenericNaming).
+			AutoExample(true))
 		AddOperations(NewOperation("", "", ""))
 		SetOperations(NewOperation("", "", ""),
 			NewOperation("", "", ""))
@@ -184,6 +186,12 @@ func TestSetGet(t *testing.T) {
 		if len(GetOption().GetRoutesOptions()) != 2 {
 			failNow(t, "Option.RoutesOptions or Option.AddRoutesOptions has a wrong behavior")
 		}
+		if GetOption().GetGenericNaming() == nil || GetOption().GetGenericNaming()("_Result", []string{"UserDto"}) != "ResultOfUserDto" {
+			failNow(t, "Option.GenericNaming has a wrong behavior")
+		}
+		if !GetOption().GetAutoExample() {
+			failNow(t, "Option.AutoExample has a wrong behavior")
+		}
 		ro := GetOption().GetRoutesOptions()
 		if ro[0].GetRoute() != "/user" || ro[1].GetRoute() != "/user/{id}" {
 			failNow(t, "NewRoutesOption or RoutesOption.Routes has a wrong behavior")
@@ -467,7 +475,10 @@ func TestSetGet(t *testing.T) {
 
 	t.Run("Set and get in definition.go", func(t *testing.T) {
 		CleanupDocument()
-		AddDefinitions(NewDefinition("Result", "A global response"),
+		AddDefinitions(NewDefinition("Result", "A global response").
+			AddProperties(NewProperty("meta", "object", false, "").InlineObject(NewInlineObject(NewProperty("a", "string", true, "")).
+				Properties(NewProperty("total", "integer", true, "")).
+				AddProperties(NewProperty("page", "integer", true, "")))),
 			NewDefinition("_Result", "A global response with generics").
 				XMLRepr(NewXMLRepr("").
 					Name("Result").
@@ -476,6 +487,8 @@ func TestSetGet(t *testing.T) {
 					Attribute(true).
 					Wrapped(true)).
 				Generics("T", "E").
+				Extends("Result").
+				Discriminator("kind", map[string]string{"a": "A"}).
 				Properties(NewProperty("data", "T", true, "response data"),
 					NewProperty("error", "E", false, "response error")))
 		AddDefinitions(NewDefinition("", "").
@@ -559,6 +572,16 @@ func TestSetGet(t *testing.T) {
 		if g := GetDefinitions()[1].GetGenerics(); g[0] != "T" || g[1] != "E" {
 			failNow(t, "Definition.Generics has a wrong behavior")
 		}
+		if e := GetDefinitions()[1].GetExtends(); len(e) != 1 || e[0] != "Result" {
+			failNow(t, "Definition.Extends has a wrong behavior")
+		}
+		if i := GetDefinitions()[0].GetProperties()[0].GetInlineObject(); i == nil || len(i.GetProperties()) != 2 ||
+			i.GetProperties()[0].GetName() != "total" || i.GetProperties()[1].GetName() != "page" {
+			failNow(t, "Property.InlineObject has a wrong behavior")
+		}
+		if d, m := GetDefinitions()[1].GetDiscriminator(), GetDefinitions()[1].GetDiscriminatorMapping(); d != "kind" || len(m) != 1 || m["a"] != "A" {
+			failNow(t, "Definition.Discriminator has a wrong behavior")
+		}
 		if p := GetDefinitions()[1].GetProperties(); p[0].GetName() != "data" || p[0].GetType() != "T" || p[0].GetRequired() != true || p[0].GetDesc() != "response data" ||
 			p[1].GetName() != "error" || p[1].GetType() != "E" || p[1].GetRequired() != false || p[1].GetDesc() != "response error" {
 			failNow(t, "Definition.NewProperty has a wrong behavior")
@@ -695,5 +718,29 @@ func TestSetGet(t *testing.T) {
 		if opt.GetItemOption() == nil || opt.GetItemOption().GetItemOption() != nil {
 			failNow(t, "ItemOption.ItemOption has a wrong behavior")
 		}
+
+		enum := NewEnumDefinition("Gender", "integer", 0, 1).EnumType("string").Enum("Secret", "Male", "Female").
+			EnumVarNames("GenderSecret", "GenderMale", "GenderFemale").EnumDescs("secret", "male", "female")
+		if enum.GetName() != "Gender" || enum.GetEnumType() != "string" {
+			failNow(t, "NewEnumDefinition or Definition.EnumType has a wrong behavior")
+		}
+		if e := enum.GetEnum(); len(e) != 3 || e[0] != "Secret" || e[2] != "Female" {
+			failNow(t, "Definition.Enum has a wrong behavior")
+		}
+		if n := enum.GetEnumVarNames(); len(n) != 3 || n[1] != "GenderMale" {
+			failNow(t, "Definition.EnumVarNames has a wrong behavior")
+		}
+		if d := enum.GetEnumDescs(); len(d) != 3 || d[2] != "female" {
+			failNow(t, "Definition.EnumDescs has a wrong behavior")
+		}
+
+		prop = NewProperty("birthday", "string#date?", true, "")
+		if prop.GetType() != "string#date" || !prop.GetNullable() {
+			failNow(t, "NewProperty with nullable type has a wrong behavior")
+		}
+		prop.Nullable(false).ReadOnly(true).WriteOnly(true)
+		if prop.GetNullable() || !prop.GetReadOnly() || !prop.GetWriteOnly() {
+			failNow(t, "Property.Nullable, Property.ReadOnly or Property.WriteOnly has a wrong behavior")
+		}
 	})
 }
@@ -0,0 +1,318 @@
+package goapidoc
+
+import (
+	"math"
+	"strings"
+)
+
+// ==========
+// ExampleFor
+// ==========
+
+// ExampleFor synthesizes an example json value for given type, such as `integer#int64`, `UserDto[]` and `_Result<_Page<UserDto>>`. The
+// property examples, defaults, enums, formats and length or range constraints are respected, and nil is returned if the type is invalid.
+func (d *Document) ExampleFor(typ string) interface{} {
+	if err := checkApiType(typ); err != nil {
+		return nil
+	}
+	b, err := newExampleBuilder(d, typ)
+	if err != nil {
+		return nil
+	}
+	return b.build(typ, &valueRule{}, nil, nil)
+}
+
+// ExampleFor synthesizes an example json value for given type of global Document.
+func ExampleFor(typ string) interface{} {
+	return _document.ExampleFor(typ)
+}
+
+// exampleBuilder represents a builder which synthesizes the example values from the prehandled definitions.
+type exampleBuilder struct {
+	defMap   map[string]*Definition
+	naming   GenericNamingFunc
+	visiting map[string]bool // definitions being built, used to stop the recursive definitions
+}
+
+// newExampleBuilder prehandles all the definitions of given Document with some extra types, and creates an exampleBuilder.
+func newExampleBuilder(doc *Document, extraTypes ...string) (*exampleBuilder, error) {
+	definitions, err := prehandleAllDefinitions(doc, extraTypes...)
+	if err != nil {
+		return nil, err
+	}
+	defMap := make(map[string]*Definition, len(definitions))
+	for _, def := range definitions {
+		defMap[def.name] = def
+	}
+	return &exampleBuilder{defMap: defMap, naming: genericNamingOf(doc), visiting: make(map[string]bool)}, nil
+}
+
+// build synthesizes the example value of given type, rule and inline object, notes that the example, default and enum values are not
+// included in valueRule, so they are passed by the caller if needed.
+func (b *exampleBuilder) build(typ string, rule *valueRule, inline *InlineObject, preset interface{}) interface{} {
+	if preset != nil {
+		return preset
+	}
+	if inline != nil {
+		return b.buildInlineObject(inlineObjectDepth(typ), rule, inline.properties)
+	}
+	at, err := parseApiType(typ)
+	if err != nil {
+		return nil
+	}
+	return b.buildType(at, rule)
+}
+
+// presetOf returns the preset example value from given example, default and enum values in order.
+func presetOf(example, defaul interface{}, enum []interface{}) interface{} {
+	if example != nil {
+		return example
+	}
+	if defaul != nil {
+		return defaul
+	}
+	if len(enum) > 0 {
+		return enum[0]
+	}
+	return nil
+}
+
+func (b *exampleBuilder) buildType(at *apiType, rule *valueRule) interface{} {
+	switch at.kind {
+	case apiPrimeKind:
+		if len(rule.enum) > 0 {
+			return rule.enum[0]
+		}
+		return b.buildPrime(at.prime, rule)
+	case apiArrayKind:
+		itemRule, preset := ruleOfItemOption(rule.itemOption), interface{}(nil)
+		if o := rule.itemOption; o != nil {
+			preset = presetOf(o.example, o.defaul, o.enum)
+		}
+		item := preset
+		if item == nil {
+			item = b.buildType(at.array.item, itemRule)
+		}
+		return repeatItem(item, rule)
+	case apiMapKind:
+		key := "key"
+		if at.mapp.key.prime.typ != STRING {
+			key = "0"
+		}
+		return map[string]interface{}{key: b.buildType(at.mapp.value, &valueRule{})}
+	case apiUnionKind:
+		return b.buildType(at.union.items[0], &valueRule{})
+	case apiObjectKind:
+		return b.buildObject(at)
+	}
+	return nil
+}
+
+func (b *exampleBuilder) buildPrime(prime *apiPrime, rule *valueRule) interface{} {
+	if prime.format != "" {
+		if f := GetFormat(prime.format, prime.typ); f != nil && f.example != nil {
+			switch prime.typ {
+			case STRING:
+				return f.example
+			case INTEGER, NUMBER:
+				if n, ok := floatOf(f.example); ok && (prime.typ == NUMBER || n == math.Trunc(n)) {
+					c := &valueChecker{}
+					if c.checkNumber("", n, rule); len(c.issues) == 0 {
+						return f.example // only when the range constraints are satisfied
+					}
+				}
+			}
+		}
+	}
+	switch prime.typ {
+	case STRING:
+		s := "string"
+		if rule.minLength != nil && len(s) < *rule.minLength {
+			s += strings.Repeat("s", *rule.minLength-len(s))
+		}
+		if rule.maxLength != nil && len(s) > *rule.maxLength {
+			s = s[:*rule.maxLength]
+		}
+		return s
+	case INTEGER, NUMBER:
+		f := exampleNumber(rule, prime.typ == INTEGER)
+		if prime.typ == INTEGER {
+			return int64(f)
+		}
+		return f
+	case BOOLEAN:
+		return true
+	case OBJECT:
+		return map[string]interface{}{}
+	}
+	return nil // file
+}
+
+// exampleNumber returns a number which satisfies the range and multipleOf constraints, 0 is preferred.
+func exampleNumber(rule *valueRule, integer bool) float64 {
+	step := 1.0
+	if rule.multipleOf > 0 {
+		step = rule.multipleOf
+	} else if !integer {
+		step = 0.5
+	}
+	f := 0.0
+	if rule.minimum != nil && (f < *rule.minimum || (rule.exclusiveMin && f <= *rule.minimum)) {
+		f = math.Ceil(*rule.minimum/step) * step
+		if rule.exclusiveMin && f <= *rule.minimum {
+			f += step
+		}
+	}
+	if rule.maximum != nil && (f > *rule.maximum || (rule.exclusiveMax && f >= *rule.maximum)) {
+		f = math.Floor(*rule.maximum/step) * step
+		if rule.exclusiveMax && f >= *rule.maximum {
+			f -= step
+		}
+	}
+	if integer && f != math.Trunc(f) {
+		f = math.Ceil(f)
+	}
+	return f
+}
+
+// repeatItem returns an array with the item repeated, the length is one by default and is limited by minItems and maxItems.
+func repeatItem(item interface{}, rule *valueRule) []interface{} {
+	length := 1
+	if item == nil {
+		length = 0 // recursive item
+	}
+	if rule.minItems != nil && length < *rule.minItems {
+		length = *rule.minItems
+	}
+	if rule.maxItems != nil && length > *rule.maxItems {
+		length = *rule.maxItems
+	}
+	out := make([]interface{}, 0, length)
+	for i := 0; i < length; i++ {
+		out = append(out, item)
+	}
+	return out
+}
+
+// buildObject synthesizes the example of given object type, enum definition is built as its first enum value, and the recursive
+// definition is built as nil.
+func (b *exampleBuilder) buildObject(at *apiType) interface{} {
+	name := definitionNameOf(at, b.naming)
+	def, ok := b.defMap[name]
+	if !ok || b.visiting[name] {
+		return nil
+	}
+	if def.enumType != "" {
+		if len(def.enums) > 0 {
+			return def.enums[0]
+		}
+		enumAt, err := parseApiType(def.enumType)
+		if err != nil {
+			return nil
+		}
+		return b.buildType(enumAt, &valueRule{})
+	}
+	b.visiting[name] = true
+	defer delete(b.visiting, name)
+	out := make(map[string]interface{}, len(def.properties))
+	b.buildDefinition(out, def, make(map[string]bool))
+	return out
+}
+
+// buildDefinition fills the properties of given definition and the definitions it extends into out, visited is used to stop the extending
+// loop.
+func (b *exampleBuilder) buildDefinition(out map[string]interface{}, def *Definition, visited map[string]bool) {
+	if visited[def.name] {
+		return // extending loop
+	}
+	visited[def.name] = true
+	for _, ext := range def.extends {
+		at, err := parseApiType(ext)
+		if err != nil || at.kind != apiObjectKind {
+			continue
+		}
+		if extDef, ok := b.defMap[definitionNameOf(at, b.naming)]; ok {
+			b.buildDefinition(out, extDef, visited)
+		}
+	}
+	b.buildProperties(out, def.properties)
+}
+
+func (b *exampleBuilder) buildProperties(out map[string]interface{}, properties []*Property) {
+	for _, prop := range properties {
+		value := b.build(prop.typ, ruleOfProperty(prop), prop.inlineObject, presetOf(prop.example, prop.defaul, prop.enum))
+		if value == nil && !prop.required {
+			continue // recursive optional property
+		}
+		out[prop.name] = value
+	}
+}
+
+func (b *exampleBuilder) buildInlineObject(arrayDepth int, rule *valueRule, properties []*Property) interface{} {
+	if arrayDepth > 0 {
+		return repeatItem(b.buildInlineObject(arrayDepth-1, ruleOfItemOption(rule.itemOption), properties), rule)
+	}
+	out := make(map[string]interface{}, len(properties))
+	b.buildProperties(out, properties)
+	return out
+}
+
+// ================
+// document filling
+// ================
+
+// fillDocumentExamples returns a shallow-copied Document whose missing request examples and response examples are filled by the synthesized
+// examples, only when the auto example option is enabled (or always is true) and the mime type is json.
+func fillDocumentExamples(doc *Document, always bool) (*Document, error) {
+	opt := doc.option
+	if opt == nil {
+		opt = NewOption()
+	}
+	if !opt.autoExample && !always {
+		return doc, nil
+	}
+	b, err := newExampleBuilder(doc)
+	if err != nil {
+		return nil, err
+	}
+	out := *doc
+	out.operations = make([]*Operation, 0, len(doc.operations))
+	for _, op := range doc.operations {
+		cloned := *op
+		consume, produce := firstMime(op.consumes, opt.consumes), firstMime(op.produces, opt.produces)
+		if cloned.reqExample == nil && isJsonContentType(consume) {
+			for _, p := range op.params {
+				if p.in == BODY {
+					cloned.reqExample = b.build(p.typ, ruleOfParam(p), p.inlineObject, presetOf(p.example, p.defaul, p.enum))
+					break
+				}
+			}
+		}
+		if isJsonContentType(produce) {
+			cloned.responses = make([]*Response, 0, len(op.responses))
+			for _, r := range op.responses {
+				if len(r.examples) == 0 && (r.typ != "" || r.inlineObject != nil) {
+					if example := b.build(r.typ, &valueRule{}, r.inlineObject, nil); example != nil {
+						filled := *r
+						filled.examples = []*ResponseExample{{mime: produce, example: example}}
+						r = &filled
+					}
+				}
+				cloned.responses = append(cloned.responses, r)
+			}
+		}
+		out.operations = append(out.operations, &cloned)
+	}
+	return &out, nil
+}
+
+// firstMime returns the first mime type of given operation mime types or document mime types, json is used by default.
+func firstMime(opMimes, docMimes []string) string {
+	if len(opMimes) > 0 {
+		return opMimes[0]
+	}
+	if len(docMimes) > 0 {
+		return docMimes[0]
+	}
+	return JSON
+}
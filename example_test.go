@@ -0,0 +1,174 @@
+package goapidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExampleFor(t *testing.T) {
+	RegisterFormat("x-port", INTEGER, nil).Example(8080)
+	RegisterFormat("x-ratio", NUMBER, nil).Example(0.75)
+	defer UnregisterFormat("x-port", INTEGER)
+	defer UnregisterFormat("x-ratio", NUMBER)
+
+	doc := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).
+		Option(NewOption().GenericNaming(OfGenericNaming)).
+		Definitions(
+			NewDefinition("Status", "status").EnumType("integer").Enum(1, 2),
+			NewDefinition("_Result", "result").Generics("T").Properties(
+				NewProperty("code", "integer#int32", true, "code").Example(200),
+				NewProperty("message", "string", true, "message").Default("success"),
+				NewProperty("data", "T", true, "data"),
+			),
+			NewDefinition("_Page", "page").Generics("T").Properties(
+				NewProperty("page", "integer#int32", true, "page").Minimum(1),
+				NewProperty("limit", "integer#int32", true, "limit").Minimum(10).Maximum(20).MultipleOf(5).ExclusiveMin(true),
+				NewProperty("data", "T[]", true, "data").MinItems(2),
+			),
+			NewDefinition("UserDto", "user").Properties(
+				NewProperty("uid", "integer#int64", true, "uid"),
+				NewProperty("username", "string", true, "username").MinLength(8),
+				NewProperty("nickname", "string", true, "nickname").MaxLength(3),
+				NewProperty("gender", "string", true, "gender").Enum("male", "female"),
+				NewProperty("status", "Status", true, "status"),
+				NewProperty("birthday", "string#date", true, "birthday"),
+				NewProperty("created_at", "string#date-time", true, "created_at"),
+				NewProperty("score", "number", true, "score").Minimum(0.2).ExclusiveMin(true),
+				NewProperty("tags", "map<string, boolean>", true, "tags"),
+				NewProperty("address", "object", true, "address").InlineObject(NewInlineObject(NewProperty("city", "string", true, "city"))),
+			),
+			NewDefinition("TreeNode", "tree node").Properties(
+				NewProperty("value", "string|integer", true, "value"),
+				NewProperty("parent", "TreeNode", false, "parent"),
+				NewProperty("children", "TreeNode[]", true, "children"),
+			),
+			NewDefinition("Server", "server").Properties(
+				NewProperty("port", "integer#x-port", true, "port"),
+				NewProperty("admin_port", "integer#x-port", true, "admin port").Maximum(1024),
+				NewProperty("ratio", "number#x-ratio", true, "ratio"),
+			),
+		).
+		Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "TreeNode"), NewResponse(201, "_Result<_Page<UserDto>>")))
+
+	user := `{"address":{"city":"string"},"birthday":"2006-01-02","created_at":"2006-01-02T15:04:05Z","gender":"male","nickname":"str",` +
+		`"score":0.5,"status":1,"tags":{"key":true},"uid":0,"username":"stringss"}`
+	for _, tc := range []struct {
+		giveType string
+		wantJson string
+	}{
+		{"string", `"string"`},
+		{"integer#int64", `0`},
+		{"number", `0`},
+		{"boolean", `true`},
+		{"string#uuid", `"3fa85f64-5717-4562-b3fc-2c963f66afa6"`},
+		{"object", `{}`},
+		{"string[][]", `[["string"]]`},
+		{"map<integer, string>", `{"0":"string"}`},
+		{"oneOf<integer, string>", `0`},
+		{"Status", `1`},
+		{"integer#x-port", `8080`},
+		{"Server", `{"admin_port":0,"port":8080,"ratio":0.75}`},
+		{"TreeNode", `{"children":[],"value":"string"}`},
+		{"_Result<_Page<UserDto>>", `{"code":200,"data":{"data":[` + user + "," + user + `],"limit":15,"page":1},"message":"success"}`},
+		{"_Result", `{"code":200,"data":{},"message":"success"}`},
+		{"Unknown", `null`},
+		{"Invalid<", `null`},
+	} {
+		t.Run(tc.giveType, func(t *testing.T) {
+			bs, err := json.Marshal(doc.ExampleFor(tc.giveType))
+			if err != nil {
+				failNow(t, "ExampleFor returns a value which cannot be marshaled: "+err.Error())
+			}
+			if string(bs) != tc.wantJson {
+				failNow(t, "ExampleFor returns a wrong value for "+tc.giveType+": "+string(bs))
+			}
+		})
+	}
+
+	t.Run("Example conforms to document", func(t *testing.T) {
+		c, err := newValueChecker(doc, false)
+		if err != nil {
+			failNow(t, "newValueChecker failed: "+err.Error())
+		}
+		bs, _ := json.Marshal(doc.ExampleFor("_Result<_Page<UserDto>>"))
+		value, _ := decodeJson(bs)
+		c.checkValue(BODY, value, "_Result<_Page<UserDto>>", &valueRule{}, nil)
+		if len(c.issues) != 0 {
+			failNow(t, "ExampleFor returns a value which does not conform to the document: "+c.issues[0].String())
+		}
+	})
+}
+
+func TestExampleForDeepExtends(t *testing.T) {
+	doc := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "D0")))
+	for i := 0; i < 40; i++ {
+		def := NewDefinition(fmt.Sprintf("D%d", i), "").Properties(NewProperty(fmt.Sprintf("p%d", i), "integer", true, ""))
+		if i < 39 {
+			def.Extends(fmt.Sprintf("D%d", i+1))
+		}
+		doc.AddDefinitions(def)
+	}
+	doc.AddDefinitions(NewDefinition("Loop1", "").Extends("Loop2").Properties(NewProperty("a", "string", true, "")),
+		NewDefinition("Loop2", "").Extends("Loop1").Properties(NewProperty("b", "string", true, "")))
+
+	if example, ok := doc.ExampleFor("D0").(map[string]interface{}); !ok || len(example) != 40 || example["p39"] == nil {
+		failNow(t, "ExampleFor does not include all the properties of a deep extending chain")
+	}
+	if example, ok := doc.ExampleFor("Loop1").(map[string]interface{}); !ok || len(example) != 2 {
+		failNow(t, "ExampleFor get a wrong example for an extending loop")
+	}
+}
+
+func TestGenerateAutoExample(t *testing.T) {
+	SetDocument("localhost", "/", NewInfo("test", "", "1.0"))
+	SetOption(NewOption().AutoExample(true))
+	SetDefinitions(NewDefinition("User", "user").Properties(
+		NewProperty("id", "integer#int64", true, "id").Example(1),
+		NewProperty("name", "string", true, "name"),
+	))
+	SetOperations(
+		NewPostOperation("/user", "create user").
+			Params(NewBodyParam("body", "User", true, "user")).
+			Responses(
+				NewResponse(200, "User"),
+				NewResponse(201, "User").Examples(NewResponseExample(JSON, map[string]interface{}{"id": 2})),
+				NewResponse(204, ""),
+			),
+		NewGetOperation("/user.xml", "get user in xml").Produces(XML).
+			Responses(NewResponse(200, "User")),
+	)
+	defer CleanupDocument()
+
+	swag, err := GenerateSwaggerJson()
+	if err != nil {
+		failNow(t, "GenerateSwaggerJson failed: "+err.Error())
+	}
+	for _, want := range []string{`"id": 1`, `"name": "string"`, `"id": 2`} {
+		if !strings.Contains(string(swag), want) {
+			failNow(t, "Swagger document does not contain the auto example "+want)
+		}
+	}
+	if strings.Count(string(swag), `"examples"`) != 2 {
+		failNow(t, "Swagger document contains a wrong number of response examples")
+	}
+
+	apib, err := GenerateApib()
+	if err != nil {
+		failNow(t, "GenerateApib failed: "+err.Error())
+	}
+	if strings.Count(string(apib), `"name": "string"`) != 2 {
+		failNow(t, "API Blueprint document does not contain the auto request and response examples")
+	}
+
+	SetOption(NewOption())
+	swag, _ = GenerateSwaggerJson()
+	if strings.Contains(string(swag), `"name": "string"`) {
+		failNow(t, "Swagger document contains auto examples without AutoExample option")
+	}
+	apib, _ = GenerateApib()
+	if strings.Count(string(apib), `"name": "string"`) != 2 {
+		failNow(t, "API Blueprint document does not contain the auto examples without AutoExample option")
+	}
+}
@@ -0,0 +1,132 @@
+package goapidoc
+
+import (
+	"regexp"
+	"sync"
+)
+
+// ======
+// Format
+// ======
+
+// Format represents a registered format of primitive type, such as `uuid` in `string#uuid`, the pattern is used to validate the values in
+// this format, and the example is used to generate examples.
+type Format struct {
+	name    string
+	typ     string
+	pattern *regexp.Regexp
+	example interface{}
+}
+
+// GetName returns the name from Format.
+func (f *Format) GetName() string { return f.name }
+
+// GetType returns the primitive type from Format.
+func (f *Format) GetType() string { return f.typ }
+
+// GetPattern returns the pattern from Format.
+func (f *Format) GetPattern() *regexp.Regexp { return f.pattern }
+
+// GetExample returns the example from Format.
+func (f *Format) GetExample() interface{} { return f.example }
+
+// Pattern sets the pattern in Format.
+func (f *Format) Pattern(pattern *regexp.Regexp) *Format {
+	f.pattern = pattern
+	return f
+}
+
+// Example sets the example in Format.
+func (f *Format) Example(example interface{}) *Format {
+	f.example = example
+	return f
+}
+
+// Match checks whether given value string matches the pattern of Format, it always returns true if the format has no pattern.
+func (f *Format) Match(value string) bool {
+	return f.pattern == nil || f.pattern.MatchString(value)
+}
+
+// _formats is a global registry of formats, which maps type#format to Format.
+var (
+	_formats   = make(map[string]*Format)
+	_formatsMu sync.RWMutex
+)
+
+// RegisterFormat registers a format of given primitive type (integer, number, string or boolean) with an optional pattern, and returns the
+// registered Format, which will replace the existed one with the same name and type. Notes that formats which are not registered in type
+// names, such as `string#datetime`, will be warned when validating and generating.
+func RegisterFormat(name, typ string, pattern *regexp.Regexp) *Format {
+	f := &Format{name: name, typ: typ, pattern: pattern}
+	_formatsMu.Lock()
+	_formats[typ+"#"+name] = f
+	_formatsMu.Unlock()
+	return f
+}
+
+// UnregisterFormat removes the registered Format of given name and primitive type, it does nothing if not found.
+func UnregisterFormat(name, typ string) {
+	_formatsMu.Lock()
+	delete(_formats, typ+"#"+name)
+	_formatsMu.Unlock()
+}
+
+// GetFormat returns the registered Format of given name and primitive type, returns nil if not found.
+func GetFormat(name, typ string) *Format {
+	_formatsMu.RLock()
+	defer _formatsMu.RUnlock()
+	return _formats[typ+"#"+name]
+}
+
+func init() {
+	// https://swagger.io/specification/v2/#data-types
+	RegisterFormat(INT32, INTEGER, nil).Example(int32(0))
+	RegisterFormat(INT64, INTEGER, nil).Example(int64(0))
+	RegisterFormat(FLOAT, NUMBER, nil).Example(0.0)
+	RegisterFormat(DOUBLE, NUMBER, nil).Example(0.0)
+	RegisterFormat(BYTE, STRING, regexp.MustCompile(`^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$`)).Example("U3dhZ2dlciByb2Nrcw==")
+	RegisterFormat(BINARY, STRING, nil)
+	RegisterFormat(DATE, STRING, regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)).Example("2006-01-02")
+	RegisterFormat(DATETIME, STRING, regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[Tt ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:[Zz]|[+-]\d{2}:\d{2})$`)).Example("2006-01-02T15:04:05Z")
+	RegisterFormat(PASSWORD, STRING, nil).Example("********")
+
+	// commonly used formats
+	RegisterFormat(EMAIL, STRING, regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)).Example("user@example.com")
+	RegisterFormat(UUID, STRING, regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)).Example("3fa85f64-5717-4562-b3fc-2c963f66afa6")
+	RegisterFormat(URI, STRING, regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.\-]*:\S*$`)).Example("https://example.com")
+	RegisterFormat(IPV4, STRING, regexp.MustCompile(`^(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)$`)).Example("127.0.0.1")
+	RegisterFormat(IPV6, STRING, regexp.MustCompile(`^[0-9a-fA-F:.]*:[0-9a-fA-F:.]*$`)).Example("::1")
+	RegisterFormat(HOSTNAME, STRING, regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?)*$`)).Example("example.com")
+	RegisterFormat(DECIMAL, STRING, regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)).Example("0.0")
+}
+
+// checkApiTypeFormats checks the formats of all primitive types in given apiType, and returns the first type whose format is not
+// registered, returns nil if all formats are registered.
+func checkApiTypeFormats(at *apiType) *apiType {
+	switch at.kind {
+	case apiPrimeKind:
+		if at.prime.format != "" && GetFormat(at.prime.format, at.prime.typ) == nil {
+			return at
+		}
+	case apiArrayKind:
+		return checkApiTypeFormats(at.array.item)
+	case apiMapKind:
+		if unknown := checkApiTypeFormats(at.mapp.key); unknown != nil {
+			return unknown
+		}
+		return checkApiTypeFormats(at.mapp.value)
+	case apiObjectKind:
+		for _, gen := range at.object.generics {
+			if unknown := checkApiTypeFormats(gen); unknown != nil {
+				return unknown
+			}
+		}
+	case apiUnionKind:
+		for _, item := range at.union.items {
+			if unknown := checkApiTypeFormats(item); unknown != nil {
+				return unknown
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package goapidoc
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		giveName  string
+		giveType  string
+		giveValue string
+		wantFound bool
+		wantMatch bool
+	}{
+		{INT64, INTEGER, "1", true, true},
+		{DATE, STRING, "2006-01-02", true, true},
+		{DATE, STRING, "2006/01/02", true, false},
+		{DATETIME, STRING, "2006-01-02T15:04:05+08:00", true, true},
+		{DATETIME, STRING, "2006-01-02", true, false},
+		{UUID, STRING, "3fa85f64-5717-4562-b3fc-2c963f66afa6", true, true},
+		{UUID, STRING, "3fa85f64", true, false},
+		{EMAIL, STRING, "user@example.com", true, true},
+		{EMAIL, STRING, "user", true, false},
+		{IPV4, STRING, "192.168.0.1", true, true},
+		{IPV4, STRING, "256.0.0.1", true, false},
+		{DECIMAL, STRING, "-1.25", true, true},
+		{DECIMAL, STRING, "1.", true, false},
+		{"datetime", STRING, "", false, false},
+		{UUID, INTEGER, "", false, false},
+	} {
+		t.Run(tc.giveType+"#"+tc.giveName, func(t *testing.T) {
+			f := GetFormat(tc.giveName, tc.giveType)
+			if (f != nil) != tc.wantFound {
+				failNow(t, "GetFormat get a wrong result for "+tc.giveType+"#"+tc.giveName)
+			}
+			if f != nil && f.Match(tc.giveValue) != tc.wantMatch {
+				failNow(t, "Format.Match get a wrong result for "+tc.giveValue)
+			}
+		})
+	}
+
+	f := RegisterFormat("x-phone", STRING, regexp.MustCompile(`^\d{11}$`)).Example("13800138000")
+	defer UnregisterFormat("x-phone", STRING)
+	if GetFormat("x-phone", STRING) != f || f.GetName() != "x-phone" || f.GetType() != STRING || f.GetExample() != "13800138000" {
+		failNow(t, "RegisterFormat has a wrong behavior")
+	}
+	if !f.Match("13800138000") || f.Match("138") || !f.Pattern(nil).Match("138") || f.GetPattern() != nil {
+		failNow(t, "Format.Match or Format.Pattern has a wrong behavior")
+	}
+
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+		NewOperation("get", "/", "s").
+			Params(NewQueryParam("q", "string#datetime", true, ""), NewQueryParam("phone", "string#x-phone", true, "")).
+			Responses(NewResponse(200, "Result<integer#int65>").AddHeaders(NewResponseHeader("X-Id", "string#uuid", ""))),
+	).AddDefinitions(
+		NewDefinition("Result", "").Generics("T").Properties(NewProperty("data", "T", true, ""), NewProperty("at", "string#date-time", true, "")),
+		NewEnumDefinition("Code", "string#code", "a", "b"),
+	)
+	got := make([]string, 0, 4)
+	for _, issue := range doc.Validate() {
+		if issue.Severity == SEVERITY_WARNING {
+			got = append(got, issue.String())
+		}
+	}
+	testMatchElements(t, got, []string{
+		"[warning] operations[GET /].params[q].type: Format `datetime` of type `string` is not registered",
+		"[warning] operations[GET /].responses[200].type: Format `int65` of type `integer` is not registered",
+		"[warning] definitions[Code].enumType: Format `code` of type `string` is not registered",
+		"[warning] definitions[Code]: Definition `Code` is not used",
+	}, "Validate", "expected warnings")
+	_, err := doc.GenerateSwaggerJson()
+	testError(t, false, err, "GenerateSwaggerJson")
+
+	UnregisterFormat("x-phone", STRING)
+	if GetFormat("x-phone", STRING) != nil {
+		failNow(t, "UnregisterFormat does not remove the registered format")
+	}
+}
@@ -6,161 +6,150 @@ import (
 	"sync/atomic"
 )
 
-func checkDocument(doc *Document) {
-	if doc.host == "" {
-		panic("Host is required")
-	}
-	if !strings.HasPrefix(doc.basePath, "/") {
-		panic("BasePath must begin with a slash")
-	}
-	if doc.info == nil {
-		panic("Info is required")
-	}
-	if doc.info.title == "" {
-		panic("Info title is required")
+// DocumentError represents an error of invalid Document, which is returned when checking and generating, with the location of the invalid
+// operation, definition, param, response or property attached.
+type DocumentError struct {
+	Method     string // method of the operation
+	Route      string // route of the operation
+	Definition string // name of the definition
+	Field      string // name of the param, response, header or property
+	Message    string // error message
+}
+
+// Error returns the formatted error message with location.
+func (e *DocumentError) Error() string {
+	sb := strings.Builder{}
+	if e.Method != "" || e.Route != "" {
+		sb.WriteString(fmt.Sprintf("operation `%s %s`: ", e.Method, e.Route))
 	}
-	if doc.info.version == "" {
-		panic("Info version is required")
+	if e.Definition != "" {
+		sb.WriteString(fmt.Sprintf("definition `%s`: ", e.Definition))
 	}
-	if doc.info.license != nil {
-		if doc.info.license.name == "" {
-			panic("License name is required")
-		}
+	if e.Field != "" {
+		sb.WriteString(fmt.Sprintf("`%s`: ", e.Field))
 	}
+	sb.WriteString(e.Message)
+	return sb.String()
+}
 
-	if doc.option != nil {
-		for _, t := range doc.option.tags {
-			if t.name == "" {
-				panic("Tag name is required")
-			}
-			if t.externalDoc != nil && t.externalDoc.url == "" {
-				panic("Tag external documentation url is required")
-			}
-		}
-		for _, s := range doc.option.securities {
-			if s.title == "" {
-				panic("Security title is required")
-			}
-			if s.typ == APIKEY {
-				if s.name == "" {
-					panic("Security name is required")
-				}
-				if s.in == "" {
-					panic("Security in-location is required")
-				}
-			} else if s.typ == BASIC {
-				// pass
-			} else if s.typ == OAUTH2 {
-				if s.flow == "" {
-					panic("Security flow is required")
-				}
-				if (s.flow == IMPLICIT_FLOW || s.flow == ACCESSCODE_FLOW) && s.authorizationUrl == "" {
-					panic("Security authorizationUrl is required")
-				}
-				if (s.flow == PASSWORD_FLOW || s.flow == APPLICATION_FLOW || s.flow == ACCESSCODE_FLOW) && s.tokenUrl == "" {
-					panic("Security tokenUrl is required")
-				}
-				if len(s.scopes) == 0 {
-					panic("Empty security scopes is not allowed")
-				}
-				for _, c := range s.scopes {
-					if c.scope == "" {
-						panic("Security scope name is required")
-					}
-				}
-			} else {
-				panic("Security type `" + s.typ + "` is not supported")
-			}
-		}
-		if doc.option.externalDoc != nil && doc.option.externalDoc.url == "" {
-			panic("Document option external documentation url is required")
-		}
-		for _, ro := range doc.option.routesOptions {
-			if !strings.HasPrefix(ro.route, "/") {
-				panic("Routes options route path must begin with a slash")
-			}
-		}
+// newDocumentError creates a DocumentError with given message and without location.
+func newDocumentError(msg string) error {
+	return &DocumentError{Message: msg}
+}
+
+// errorInOperation attaches the operation location to given DocumentError.
+func errorInOperation(err error, op *Operation) error {
+	if e, ok := err.(*DocumentError); ok && e.Method == "" && e.Route == "" {
+		e.Method, e.Route = strings.ToUpper(op.method), op.route
 	}
+	return err
+}
 
-	if len(doc.operations) == 0 {
-		panic("Empty operations is not allowed")
+// errorInDefinition attaches the definition location to given DocumentError.
+func errorInDefinition(err error, definition string) error {
+	if e, ok := err.(*DocumentError); ok && e.Definition == "" {
+		e.Definition = definition
 	}
-	for _, op := range doc.operations {
-		if op.method == "" {
-			panic("Operation method is required")
-		}
-		if !strings.HasPrefix(op.route, "/") {
-			panic("Operation route path must begin with a slash")
-		}
-		if op.summary == "" {
-			panic("Operation summary is required")
-		}
+	return err
+}
 
-		for _, p := range op.params {
-			if p.name == "" {
-				panic("Request param name is required")
-			}
-			if p.in == "" {
-				panic("Request param in-location is required")
-			}
-			if p.in == PATH && (!p.required || p.allowEmpty) {
-				panic("Path param's must be non-optional and non-empty")
-			}
-			if p.typ == "" {
-				panic("Request param type is required")
-			}
-		}
+// errorInField attaches the field location to given DocumentError.
+func errorInField(err error, field string) error {
+	if e, ok := err.(*DocumentError); ok && e.Field == "" {
+		e.Field = field
+	}
+	return err
+}
 
-		if len(op.responses) == 0 {
-			panic("Empty operation response is not allowed")
-		}
-		for _, r := range op.responses {
-			if r.code == 0 {
-				panic("Response code is required")
-			}
-			for _, h := range r.headers {
-				if h.name == "" {
-					panic("Response header field name is required")
-				}
-				if h.typ == "" {
-					panic("Response header type is required")
-				}
-			}
-			for _, e := range r.examples {
-				if e.mime == "" {
-					panic("Response example mime is required")
-				}
-			}
-		}
-		if op.externalDoc != nil && op.externalDoc.url == "" {
-			panic("Operation external documentation url is required")
-		}
+// operationIdOf returns the operationId of given Operation, it will be generated from route and method if it is not set explicitly.
+func operationIdOf(op *Operation) string {
+	if op.operationId != "" {
+		return op.operationId
 	}
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(
+		op.route, "/", "-"), "{", ":"), "}", "") + "-" + strings.ToLower(op.method)
+}
 
-	for _, def := range doc.definitions {
-		if def.name == "" {
-			panic("Definition name is required")
+// operationParams returns the params of given Operation appended with the global params which are not overridden by name. Notes that
+// the global path params are only appended when the route has the matching placeholders.
+func operationParams(op *Operation, globalParams []*Param) []*Param {
+	if len(globalParams) == 0 {
+		return op.params
+	}
+	placeholders := make(map[string]bool, 2)
+	for _, matches := range routeParamRe.FindAllStringSubmatch(op.route, -1) {
+		placeholders[matches[1]] = true
+	}
+	params := append(make([]*Param, 0, len(op.params)+len(globalParams)), op.params...)
+	for _, globalParam := range globalParams {
+		if globalParam.in == PATH && !placeholders[globalParam.name] {
+			continue
 		}
-		for _, p := range def.properties {
-			if p.name == "" {
-				panic("Definition property name is required")
-			}
-			if p.typ == "" {
-				panic("Definition property type is required")
+		existed := false
+		for _, existedParam := range op.params {
+			if existedParam.name == globalParam.name {
+				existed = true
+				break
 			}
 		}
+		if !existed {
+			params = append(params, globalParam)
+		}
 	}
+	return params
 }
 
 // GenerateSwaggerYaml generates swagger yaml script and returns byte array.
 func (d *Document) GenerateSwaggerYaml() ([]byte, error) {
-	doc := buildSwagDocument(d)
+	doc, err := buildSwagDocument(d)
+	if err != nil {
+		return nil, err
+	}
 	return yamlMarshal(doc)
 }
 
 // GenerateSwaggerJson generates swagger json script and returns byte array.
 func (d *Document) GenerateSwaggerJson() ([]byte, error) {
-	doc := buildSwagDocument(d)
+	doc, err := buildSwagDocument(d)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMarshal(doc)
+}
+
+// GenerateOpenAPI3Yaml generates openapi3 yaml script and returns byte array.
+func (d *Document) GenerateOpenAPI3Yaml() ([]byte, error) {
+	doc, err := buildOas3Document(d)
+	if err != nil {
+		return nil, err
+	}
+	return yamlMarshal(doc)
+}
+
+// GenerateOpenAPI3Json generates openapi3 json script and returns byte array.
+func (d *Document) GenerateOpenAPI3Json() ([]byte, error) {
+	doc, err := buildOas3Document(d)
+	if err != nil {
+		return nil, err
+	}
+	return jsonMarshal(doc)
+}
+
+// GenerateOpenAPI31Yaml generates openapi3.1 yaml script and returns byte array.
+func (d *Document) GenerateOpenAPI31Yaml() ([]byte, error) {
+	doc, err := buildOas31Document(d)
+	if err != nil {
+		return nil, err
+	}
+	return yamlMarshal(doc)
+}
+
+// GenerateOpenAPI31Json generates openapi3.1 json script and returns byte array.
+func (d *Document) GenerateOpenAPI31Json() ([]byte, error) {
+	doc, err := buildOas31Document(d)
+	if err != nil {
+		return nil, err
+	}
 	return jsonMarshal(doc)
 }
 
@@ -195,6 +184,58 @@ func (d *Document) SaveSwaggerJson(path string) ([]byte, error) {
 	return bs, nil
 }
 
+// SaveOpenAPI3Yaml generates openapi3 yaml script and saves into file.
+func (d *Document) SaveOpenAPI3Yaml(path string) ([]byte, error) {
+	bs, err := d.GenerateOpenAPI3Yaml()
+	if err != nil {
+		return nil, err
+	}
+	err = saveFile(path, bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// SaveOpenAPI3Json generates openapi3 json script and saves into file.
+func (d *Document) SaveOpenAPI3Json(path string) ([]byte, error) {
+	bs, err := d.GenerateOpenAPI3Json()
+	if err != nil {
+		return nil, err
+	}
+	err = saveFile(path, bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// SaveOpenAPI31Yaml generates openapi3.1 yaml script and saves into file.
+func (d *Document) SaveOpenAPI31Yaml(path string) ([]byte, error) {
+	bs, err := d.GenerateOpenAPI31Yaml()
+	if err != nil {
+		return nil, err
+	}
+	err = saveFile(path, bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// SaveOpenAPI31Json generates openapi3.1 json script and saves into file.
+func (d *Document) SaveOpenAPI31Json(path string) ([]byte, error) {
+	bs, err := d.GenerateOpenAPI31Json()
+	if err != nil {
+		return nil, err
+	}
+	err = saveFile(path, bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
 // SaveApib generates apib script and saves into file.
 func (d *Document) SaveApib(path string) ([]byte, error) {
 	bs, err := d.GenerateApib()
@@ -218,6 +259,26 @@ func GenerateSwaggerJson() ([]byte, error) {
 	return _document.GenerateSwaggerJson()
 }
 
+// GenerateOpenAPI3Yaml generates openapi3 yaml script and returns byte array.
+func GenerateOpenAPI3Yaml() ([]byte, error) {
+	return _document.GenerateOpenAPI3Yaml()
+}
+
+// GenerateOpenAPI3Json generates openapi3 json script and returns byte array.
+func GenerateOpenAPI3Json() ([]byte, error) {
+	return _document.GenerateOpenAPI3Json()
+}
+
+// GenerateOpenAPI31Yaml generates openapi3.1 yaml script and returns byte array.
+func GenerateOpenAPI31Yaml() ([]byte, error) {
+	return _document.GenerateOpenAPI31Yaml()
+}
+
+// GenerateOpenAPI31Json generates openapi3.1 json script and returns byte array.
+func GenerateOpenAPI31Json() ([]byte, error) {
+	return _document.GenerateOpenAPI31Json()
+}
+
 // GenerateApib generates apib script and returns byte array.
 func GenerateApib() ([]byte, error) {
 	return _document.GenerateApib()
@@ -233,6 +294,26 @@ func SaveSwaggerJson(path string) ([]byte, error) {
 	return _document.SaveSwaggerJson(path)
 }
 
+// SaveOpenAPI3Yaml generates openapi3 yaml script and saves into file.
+func SaveOpenAPI3Yaml(path string) ([]byte, error) {
+	return _document.SaveOpenAPI3Yaml(path)
+}
+
+// SaveOpenAPI3Json generates openapi3 json script and saves into file.
+func SaveOpenAPI3Json(path string) ([]byte, error) {
+	return _document.SaveOpenAPI3Json(path)
+}
+
+// SaveOpenAPI31Yaml generates openapi3.1 yaml script and saves into file.
+func SaveOpenAPI31Yaml(path string) ([]byte, error) {
+	return _document.SaveOpenAPI31Yaml(path)
+}
+
+// SaveOpenAPI31Json generates openapi3.1 json script and saves into file.
+func SaveOpenAPI31Json(path string) ([]byte, error) {
+	return _document.SaveOpenAPI31Json(path)
+}
+
 // SaveApib generates apib script and saves into file.
 func SaveApib(path string) ([]byte, error) {
 	return _document.SaveApib(path)
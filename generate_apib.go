@@ -74,13 +74,15 @@ type apibResponse struct {
 	Produce       string
 	AdditionalDoc string
 
-	Headers  []string
-	AttrBody string
-	Example  string
+	Headers     []string
+	AttrBody    string
+	AttrMembers []string
+	Example     string
 }
 
 type apibDefinition struct {
 	Name       string
+	Type       string
 	Properties []string
 }
 
@@ -89,6 +91,8 @@ type apibSchema struct {
 	typ      string
 	required bool
 	desc     string
+	inline   *InlineObject
+	nullable bool
 
 	allowEmpty       bool
 	defaul           interface{}
@@ -112,8 +116,11 @@ type apibSchema struct {
 // type & schema & externalDoc & example
 // =====================================
 
-func buildApibType(typ string) (string, *apiType) {
-	at := parseApiType(typ)
+func buildApibType(typ string) (string, *apiType, error) {
+	at, err := parseApiType(typ)
+	if err != nil {
+		return "", nil, err
+	}
 	switch at.kind {
 	case apiPrimeKind:
 		t := at.prime.typ
@@ -123,40 +130,130 @@ func buildApibType(typ string) (string, *apiType) {
 		} else if t == INTEGER {
 			t = NUMBER
 		}
-		return t, at
+		return t, at, nil
 	case apiArrayKind:
-		t, _ := buildApibType(at.array.item.name)
-		return fmt.Sprintf("array[%s]", t), at
+		t, _, _ := buildApibType(at.array.item.name) // item has been parsed
+		return fmt.Sprintf("array[%s]", t), at, nil
 	case apiObjectKind:
-		return typ, at
+		return typ, at, nil
+	case apiMapKind, apiUnionKind:
+		return OBJECT, at, nil
+	default:
+		return "", nil, nil // unreachable
+	}
+}
+
+// buildApibMembers builds the nested members for given map or union type, notes that nested map and union will be built recurrently, and
+// returns empty string for other types.
+func buildApibMembers(at *apiType) string {
+	switch at.kind {
+	case apiMapKind:
+		/*
+			+ *key (<key type>)* (<value type>)
+			    + ...
+		*/
+		key, _, _ := buildApibType(at.mapp.key.name) // key and value have been parsed
+		value, _, _ := buildApibType(at.mapp.value.name)
+		out := fmt.Sprintf("+ *key (%s)* (%s)", key, value)
+		if members := buildApibMembers(at.mapp.value); members != "" {
+			out += "\n" + spaceIndent(1, members)
+		}
+		return out
+	case apiUnionKind:
+		/*
+			+ One Of
+			    + Include <object type>
+			    + (<other type>)
+		*/
+		if at.union.typ == ANYOF {
+			logWarning("anyOf type is not supported in API Blueprint, this will be replaced to one of.")
+		}
+		out := "+ One Of"
+		for _, item := range at.union.items {
+			if item.kind == apiObjectKind {
+				out += "\n" + spaceIndent(1, "+ Include "+item.name)
+				continue
+			}
+			typ, _, _ := buildApibType(item.name) // item has been parsed
+			out += "\n" + spaceIndent(1, fmt.Sprintf("+ (%s)", typ))
+			if members := buildApibMembers(item); members != "" {
+				out += "\n" + spaceIndent(2, members)
+			}
+		}
+		return out
 	default:
-		return "", nil // unreachable
+		return ""
+	}
+}
+
+// buildApibInlineType builds the type for given type which is used with an inline object, such as `object[]` -> `array[object]`.
+func buildApibInlineType(typ string) string {
+	out := OBJECT
+	for i := inlineObjectDepth(typ); i > 0; i-- {
+		out = fmt.Sprintf("array[%s]", out)
+	}
+	return out
+}
+
+// buildApibInlineMembers builds the nested property members for given inline object, notes that the members of array are wrapped in an
+// object member.
+func buildApibInlineMembers(typ string, inline *InlineObject) (string, error) {
+	/*
+		+ <property> (<type>)
+		+ (object)
+		    + <property> (<type>)
+	*/
+	props, err := buildApibProperties(inline.properties)
+	if err != nil {
+		return "", err
+	}
+	out := strings.Join(props, "\n")
+	if inlineObjectDepth(typ) > 0 {
+		if out == "" {
+			return "+ (object)", nil
+		}
+		return "+ (object)\n" + spaceIndent(1, out), nil
 	}
+	return out, nil
 }
 
-func buildApibSchema(schema *apibSchema, in string) string {
-	typ, at := buildApibType(schema.typ)
+func buildApibSchema(schema *apibSchema, in string) (string, error) {
+	var typ string
+	var at *apiType
+	var err error
+	if schema.inline != nil {
+		typ = buildApibInlineType(schema.typ)
+	} else {
+		typ, at, err = buildApibType(schema.typ)
+		if err != nil {
+			return "", err
+		}
+	}
 	req := "required"
 	if !schema.required {
 		req = "optional"
 	}
+	attrs := req
+	if schema.nullable {
+		attrs += ", nullable"
+	}
 	switch in {
 	case BODY:
-		return typ
+		return typ, nil
 	case HEADER:
 		if schema.example != nil {
-			return fmt.Sprintf("%s: %v", schema.name, schema.example)
+			return fmt.Sprintf("%s: %v", schema.name, schema.example), nil
 		}
 		if schema.desc == "" {
-			return fmt.Sprintf("%s: (%s, %s)", schema.name, typ, req)
+			return fmt.Sprintf("%s: (%s, %s)", schema.name, typ, req), nil
 		}
-		return fmt.Sprintf("%s: (%s, %s) - %s", schema.name, typ, req, schema.desc)
+		return fmt.Sprintf("%s: (%s, %s) - %s", schema.name, typ, req, schema.desc), nil
 	case PATH, QUERY, FORM:
 		// pass
 	}
 
 	/*
-		+ <parameter name>: `<example value>` (<type> | enum[<type>], required | optional) - <description>
+		+ <parameter name>: `<example value>` (<type> | enum[<type>], required | optional[, nullable]) - <description>
 		    <additional description>
 		    + Default: `<default value>`
 		    + Members
@@ -168,15 +265,15 @@ func buildApibSchema(schema *apibSchema, in string) string {
 		typ = fmt.Sprintf("enum[%s]", typ)
 	}
 	if schema.example != nil {
-		out.WriteString(fmt.Sprintf("+ %s: `%v` (%s, %s)", schema.name, schema.example, typ, req))
+		out.WriteString(fmt.Sprintf("+ %s: `%v` (%s, %s)", schema.name, schema.example, typ, attrs))
 	} else {
-		out.WriteString(fmt.Sprintf("+ %s (%s, %s)", schema.name, typ, req))
+		out.WriteString(fmt.Sprintf("+ %s (%s, %s)", schema.name, typ, attrs))
 	}
 	if schema.desc != "" {
 		out.WriteString(fmt.Sprintf(" - %s", schema.desc))
 	}
 	options := make([]string, 0, 4) // cap defaults to 4
-	if at.kind == apiPrimeKind && at.prime.format != "" {
+	if at != nil && at.kind == apiPrimeKind && at.prime.format != "" {
 		options = append(options, fmt.Sprintf("format: %s", at.prime.format))
 	}
 	if schema.allowEmpty {
@@ -234,8 +331,28 @@ func buildApibSchema(schema *apibSchema, in string) string {
 			out.WriteString(fmt.Sprintf("\n        + `%v`", enum))
 		}
 	}
+	members, err := buildApibBodyMember(schema.typ, schema.inline)
+	if err != nil {
+		return "", err
+	}
+	if members != "" {
+		out.WriteString("\n" + spaceIndent(1, members))
+	}
+
+	return out.String(), nil
+}
 
-	return out.String()
+// buildApibBodyMember builds the nested members for given body type if it is a map, union or inline object type, otherwise returns empty
+// string.
+func buildApibBodyMember(typ string, inline *InlineObject) (string, error) {
+	if inline != nil {
+		return buildApibInlineMembers(typ, inline)
+	}
+	at, err := parseApiType(typ)
+	if err != nil {
+		return "", nil // body type has been checked
+	}
+	return buildApibMembers(at), nil
 }
 
 func buildApiExternalDoc(doc *ExternalDoc) string {
@@ -327,6 +444,9 @@ var apibOperationTemplate = `
 
 {{ if .AttrBody }}
     + Attributes ({{ .AttrBody }})
+
+{{ range .AttrMembers }}{{ . }}
+{{ end }}
 {{ end }}
 
 {{ if .Headers }}
@@ -343,7 +463,7 @@ var apibOperationTemplate = `
 {{ end }}
 `
 
-func buildApibOperation(op *Operation, params []*Param, securities map[string]*Security) ([]byte, error) {
+func buildApibOperation(op *Operation, params []*Param, securities map[string]*Security, naming GenericNamingFunc) ([]byte, error) {
 	// prehandle operation fields
 	consume := JSON
 	if len(op.consumes) >= 1 {
@@ -391,11 +511,12 @@ func buildApibOperation(op *Operation, params []*Param, securities map[string]*S
 		Responses:     make([]*apibResponse, 0, 1),
 	}
 	for _, p := range params {
-		s := buildApibSchema(&apibSchema{
+		s, err := buildApibSchema(&apibSchema{
 			name:     p.name,
 			typ:      p.typ,
 			required: p.required,
 			desc:     p.desc,
+			inline:   p.inlineObject,
 
 			allowEmpty:       p.allowEmpty,
 			defaul:           p.defaul,
@@ -414,6 +535,9 @@ func buildApibOperation(op *Operation, params []*Param, securities map[string]*S
 			exclusiveMax:     p.exclusiveMax,
 			multipleOf:       p.multipleOf,
 		}, p.in)
+		if err != nil {
+			return nil, errorInField(err, p.name)
+		}
 		switch p.in {
 		case PATH, QUERY:
 			out.Parameters = append(out.Parameters, spaceIndent(1, s))
@@ -424,16 +548,26 @@ func buildApibOperation(op *Operation, params []*Param, securities map[string]*S
 			out.Headers = append(out.Headers, spaceIndent(3, s))
 		case BODY:
 			out.AttrBody = s
+			member, err := buildApibBodyMember(p.typ, p.inlineObject)
+			if err != nil {
+				return nil, errorInField(err, p.name)
+			}
+			if member != "" {
+				out.Forms = append(out.Forms, spaceIndent(2, member))
+			}
 		}
 	}
-	for _, r := range op.responses {
+	for _, r := range nameGenericResponses(op.responses, naming) {
 		desc := r.desc
 		if desc == "" {
 			desc = strconv.Itoa(r.code) + " " + http.StatusText(r.code)
 		}
 		headers := make([]string, 0, len(r.headers))
 		for _, h := range r.headers {
-			s := buildApibSchema(&apibSchema{name: h.name, typ: h.typ, desc: h.desc, required: true, example: h.example}, HEADER)
+			s, err := buildApibSchema(&apibSchema{name: h.name, typ: h.typ, desc: h.desc, required: true, example: h.example}, HEADER)
+			if err != nil {
+				return nil, errorInField(err, h.name)
+			}
 			headers = append(headers, spaceIndent(3, s))
 		}
 		example := ""
@@ -443,13 +577,29 @@ func buildApibOperation(op *Operation, params []*Param, securities map[string]*S
 				break
 			}
 		}
+		attrBody, attrMembers := "", make([]string, 0, 1)
+		if r.inlineObject != nil || r.typ != "" {
+			s, err := buildApibSchema(&apibSchema{typ: r.typ, inline: r.inlineObject}, BODY)
+			if err != nil {
+				return nil, errorInField(err, strconv.Itoa(r.code))
+			}
+			attrBody = s
+			member, err := buildApibBodyMember(r.typ, r.inlineObject)
+			if err != nil {
+				return nil, errorInField(err, strconv.Itoa(r.code))
+			}
+			if member != "" {
+				attrMembers = append(attrMembers, spaceIndent(2, member))
+			}
+		}
 		out.Responses = append(out.Responses, &apibResponse{
 			Code:          r.code,
 			Description:   desc,
 			Produce:       produce,
 			AdditionalDoc: r.additionalDoc,
 			Headers:       headers,
-			AttrBody:      buildApibSchema(&apibSchema{typ: r.typ}, BODY),
+			AttrBody:      attrBody,
+			AttrMembers:   attrMembers,
 			Example:       example,
 		})
 	}
@@ -502,20 +652,7 @@ func buildApibGroups(doc *Document) ([]byte, error) {
 	// extract and process params from all operations
 	operationParas := make(map[*Operation][]*Param)
 	for _, op := range doc.operations {
-		params := op.params
-		for _, globalParam := range globalParams {
-			existed := false
-			for _, existedParam := range params {
-				if existedParam.name == globalParam.name {
-					existed = true
-					break
-				}
-			}
-			if !existed {
-				params = append(params, globalParam)
-			}
-		}
-		operationParas[op] = params
+		operationParas[op] = nameGenericParams(operationParams(op, globalParams), genericNamingOf(doc))
 	}
 
 	// put all operationParas to trmoMap splitting by tag, route and method
@@ -573,9 +710,9 @@ func buildApibGroups(doc *Document) ([]byte, error) {
 				op := moMap.MustGet(method).(*Operation)
 				rawRoute = op.route
 				summaries = append(summaries, op.summary)
-				bs, err := buildApibOperation(op, operationParas[op], securities)
+				bs, err := buildApibOperation(op, operationParas[op], securities, genericNamingOf(doc))
 				if err != nil {
-					return nil, err
+					return nil, errorInOperation(err, op)
 				}
 				moStrings = append(moStrings, fastBtos(bs))
 			}
@@ -611,52 +748,93 @@ var apibDefinitionTemplate = `
 # Data Structures
 
 {{ range . }}
-## {{ .Name }} (object)
+## {{ .Name }} ({{ .Type }})
 
 {{ range .Properties }}{{ . }}
 {{ end }}
 
 {{ end }}`
 
+// buildApibProperties builds the property members for given properties, which are used in definitions and inline objects.
+func buildApibProperties(properties []*Property) ([]string, error) {
+	props := make([]string, 0, len(properties))
+	for _, p := range properties {
+		s, err := buildApibSchema(&apibSchema{
+			name:     p.name,
+			typ:      p.typ,
+			required: p.required,
+			desc:     p.desc,
+			inline:   p.inlineObject,
+			nullable: p.nullable,
+
+			allowEmpty:       p.allowEmpty,
+			defaul:           p.defaul,
+			example:          p.example,
+			pattern:          p.pattern,
+			enum:             p.enum,
+			minLength:        p.minLength,
+			maxLength:        p.maxLength,
+			minItems:         p.minItems,
+			maxItems:         p.maxItems,
+			uniqueItems:      p.uniqueItems,
+			collectionFormat: p.collectionFormat,
+			minimum:          p.minimum,
+			maximum:          p.maximum,
+			exclusiveMin:     p.exclusiveMin,
+			exclusiveMax:     p.exclusiveMax,
+			multipleOf:       p.multipleOf,
+		}, PATH)
+		if err != nil {
+			return nil, errorInField(err, p.name)
+		}
+		props = append(props, s)
+	}
+	return props, nil
+}
+
+// buildApibEnumDefinition builds the enum data structure for given enum definition, notes that the enum variable names are not supported.
+func buildApibEnumDefinition(def *Definition) *apibDefinition {
+	/*
+		## <name> (enum[<type>])
+		+ `<enumeration value 1>` - <description>
+		+ `<enumeration value 2>`
+	*/
+	typ, _, _ := buildApibType(def.enumType) // enum type has been checked
+	members := make([]string, 0, len(def.enums))
+	for idx, e := range def.enums {
+		member := fmt.Sprintf("+ `%v`", e)
+		if idx < len(def.enumDescs) && def.enumDescs[idx] != "" {
+			member += " - " + def.enumDescs[idx]
+		}
+		members = append(members, member)
+	}
+	return &apibDefinition{Name: def.name, Type: fmt.Sprintf("enum[%s]", typ), Properties: members}
+}
+
 func buildApibDefinitions(doc *Document) ([]byte, error) {
 	// prehandle definition list
-	allSpecTypes := collectAllSpecTypes(doc)
-	clonedDefinitions := make([]*Definition, 0, len(doc.definitions))
-	for _, definition := range doc.definitions {
-		clonedDefinitions = append(clonedDefinitions, prehandleDefinition(definition)) // with generic name checked
+	newDefinitionList, err := prehandleAllDefinitions(doc)
+	if err != nil {
+		return nil, err
 	}
-	newDefinitionList := prehandleDefinitionList(clonedDefinitions, allSpecTypes)
 
 	// render definitions to apibDefinition slice
 	out := make([]*apibDefinition, 0, len(newDefinitionList))
 	for _, def := range newDefinitionList {
-		props := make([]string, 0, len(def.properties))
-		for _, p := range def.properties {
-			props = append(props, buildApibSchema(&apibSchema{
-				name:     p.name,
-				typ:      p.typ,
-				required: p.required,
-				desc:     p.desc,
-
-				allowEmpty:       p.allowEmpty,
-				defaul:           p.defaul,
-				example:          p.example,
-				pattern:          p.pattern,
-				enum:             p.enum,
-				minLength:        p.minLength,
-				maxLength:        p.maxLength,
-				minItems:         p.minItems,
-				maxItems:         p.maxItems,
-				uniqueItems:      p.uniqueItems,
-				collectionFormat: p.collectionFormat,
-				minimum:          p.minimum,
-				maximum:          p.maximum,
-				exclusiveMin:     p.exclusiveMin,
-				exclusiveMax:     p.exclusiveMax,
-				multipleOf:       p.multipleOf,
-			}, PATH))
-		}
-		out = append(out, &apibDefinition{Name: def.name, Properties: props})
+		if def.enumType != "" {
+			out = append(out, buildApibEnumDefinition(def))
+			continue
+		}
+		props := make([]string, 0, len(def.extends)+len(def.properties))
+		for _, ext := range def.extends {
+			props = append(props, "+ Include "+ext)
+		}
+		ps, err := buildApibProperties(def.properties)
+		if err != nil {
+			return nil, errorInDefinition(err, def.name)
+		}
+		props = append(props, ps...)
+		out = append(out, &apibDefinition{Name: def.name, Type: OBJECT, Properties: props})
 	}
 
 	return renderTemplate(apibDefinitionTemplate, out)
@@ -712,7 +890,13 @@ HOST: {{ .Host }}{{ .BasePath }}
 
 func buildApibDocument(doc *Document) ([]byte, error) {
 	// check
-	checkDocument(doc)
+	if err := checkDocument(doc); err != nil {
+		return nil, err
+	}
+	doc, err := fillDocumentExamples(doc, true) // the body sections of api blueprint are always filled
+	if err != nil {
+		return nil, err
+	}
 
 	// info
 	out := &apibDocument{
@@ -0,0 +1,934 @@
+package goapidoc
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type oas3Document struct {
+	OpenAPI     string                               `yaml:"openapi"                     json:"openapi"`
+	Dialect     string                               `yaml:"jsonSchemaDialect,omitempty" json:"jsonSchemaDialect,omitempty"`
+	Info        *swagInfo                            `yaml:"info"                        json:"info"`
+	Servers     []*oas3Server                        `yaml:"servers,omitempty"           json:"servers,omitempty"`
+	Tags        []*swagTag                           `yaml:"tags,omitempty"              json:"tags,omitempty"`
+	ExternalDoc *swagExternalDoc                     `yaml:"externalDocs,omitempty"      json:"externalDocs,omitempty"`
+	Operations  map[string]map[string]*oas3Operation `yaml:"paths"                       json:"paths"`
+	Components  *oas3Components                      `yaml:"components,omitempty"        json:"components,omitempty"`
+}
+
+type oas3Server struct {
+	Url         string `yaml:"url"                   json:"url"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+type oas3Components struct {
+	Schemas    map[string]*oas3Schema   `yaml:"schemas,omitempty"         json:"schemas,omitempty"`
+	Securities map[string]*oas3Security `yaml:"securitySchemes,omitempty" json:"securitySchemes,omitempty"`
+}
+
+type oas3Security struct {
+	Type        string          `yaml:"type"                  json:"type"`
+	Description string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Name        string          `yaml:"name,omitempty"        json:"name,omitempty"`
+	In          string          `yaml:"in,omitempty"          json:"in,omitempty"`
+	Scheme      string          `yaml:"scheme,omitempty"      json:"scheme,omitempty"`
+	Flows       *oas3OAuthFlows `yaml:"flows,omitempty"       json:"flows,omitempty"`
+}
+
+type oas3OAuthFlows struct {
+	Implicit          *oas3OAuthFlow `yaml:"implicit,omitempty"          json:"implicit,omitempty"`
+	Password          *oas3OAuthFlow `yaml:"password,omitempty"          json:"password,omitempty"`
+	ClientCredentials *oas3OAuthFlow `yaml:"clientCredentials,omitempty" json:"clientCredentials,omitempty"`
+	AuthorizationCode *oas3OAuthFlow `yaml:"authorizationCode,omitempty" json:"authorizationCode,omitempty"`
+}
+
+type oas3OAuthFlow struct {
+	AuthorizationUrl string            `yaml:"authorizationUrl,omitempty" json:"authorizationUrl,omitempty"`
+	TokenUrl         string            `yaml:"tokenUrl,omitempty"         json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes"                     json:"scopes"`
+}
+
+type oas3Operation struct {
+	Summary     string                   `yaml:"summary"                json:"summary"`
+	OperationId string                   `yaml:"operationId"            json:"operationId"`
+	Description string                   `yaml:"description,omitempty"  json:"description,omitempty"`
+	Servers     []*oas3Server            `yaml:"servers,omitempty"      json:"servers,omitempty"`
+	Tags        []string                 `yaml:"tags,omitempty"         json:"tags,omitempty"`
+	Securities  []map[string][]string    `yaml:"security,omitempty"     json:"security,omitempty"`
+	Deprecated  bool                     `yaml:"deprecated,omitempty"   json:"deprecated,omitempty"`
+	ExternalDoc *swagExternalDoc         `yaml:"externalDocs,omitempty" json:"externalDocs,omitempty"`
+	Parameters  []*oas3Param             `yaml:"parameters,omitempty"   json:"parameters,omitempty"`
+	RequestBody *oas3RequestBody         `yaml:"requestBody,omitempty"  json:"requestBody,omitempty"`
+	Responses   map[string]*oas3Response `yaml:"responses"              json:"responses"`
+}
+
+type oas3Param struct {
+	Name        string      `yaml:"name"                      json:"name"`
+	In          string      `yaml:"in"                        json:"in"`
+	Required    bool        `yaml:"required"                  json:"required"`
+	Description string      `yaml:"description,omitempty"     json:"description,omitempty"`
+	AllowEmpty  bool        `yaml:"allowEmptyValue,omitempty" json:"allowEmptyValue,omitempty"`
+	Style       string      `yaml:"style,omitempty"           json:"style,omitempty"`
+	Explode     *bool       `yaml:"explode,omitempty"         json:"explode,omitempty"`
+	Example     interface{} `yaml:"example,omitempty"         json:"example,omitempty"`
+	Schema      *oas3Schema `yaml:"schema"                    json:"schema"`
+}
+
+type oas3RequestBody struct {
+	Description string                    `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool                      `yaml:"required,omitempty"    json:"required,omitempty"`
+	Content     map[string]*oas3MediaType `yaml:"content"               json:"content"`
+}
+
+type oas3MediaType struct {
+	Schema  *oas3Schema `yaml:"schema,omitempty"  json:"schema,omitempty"`
+	Example interface{} `yaml:"example,omitempty" json:"example,omitempty"`
+}
+
+type oas3Response struct {
+	Description string                    `yaml:"description"       json:"description"`
+	Headers     map[string]*oas3Header    `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Content     map[string]*oas3MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+}
+
+type oas3Header struct {
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Example     interface{} `yaml:"example,omitempty"     json:"example,omitempty"`
+	Schema      *oas3Schema `yaml:"schema"                json:"schema"`
+}
+
+type oas3Schema struct {
+	Type         interface{}   `yaml:"type,omitempty"             json:"type,omitempty"` // string, or []string in 3.1
+	Format       string        `yaml:"format,omitempty"           json:"format,omitempty"`
+	Required     []string      `yaml:"required,omitempty"         json:"required,omitempty"`
+	Description  string        `yaml:"description,omitempty"      json:"description,omitempty"`
+	Nullable     bool          `yaml:"nullable,omitempty"         json:"nullable,omitempty"` // only for 3.0
+	ReadOnly     bool          `yaml:"readOnly,omitempty"         json:"readOnly,omitempty"`
+	WriteOnly    bool          `yaml:"writeOnly,omitempty"        json:"writeOnly,omitempty"`
+	Default      interface{}   `yaml:"default,omitempty"          json:"default,omitempty"`
+	Example      interface{}   `yaml:"example,omitempty"          json:"example,omitempty"`  // only for 3.0
+	Examples     []interface{} `yaml:"examples,omitempty"         json:"examples,omitempty"` // only for 3.1
+	Pattern      string        `yaml:"pattern,omitempty"          json:"pattern,omitempty"`
+	Enum         []interface{} `yaml:"enum,omitempty"             json:"enum,omitempty"`
+	MaxLength    *int          `yaml:"maxLength,omitempty"        json:"maxLength,omitempty"`
+	MinLength    *int          `yaml:"minLength,omitempty"        json:"minLength,omitempty"`
+	MaxItems     *int          `yaml:"maxItems,omitempty"         json:"maxItems,omitempty"`
+	MinItems     *int          `yaml:"minItems,omitempty"         json:"minItems,omitempty"`
+	UniqueItems  bool          `yaml:"uniqueItems,omitempty"      json:"uniqueItems,omitempty"`
+	Maximum      *float64      `yaml:"maximum,omitempty"          json:"maximum,omitempty"`
+	Minimum      *float64      `yaml:"minimum,omitempty"          json:"minimum,omitempty"`
+	ExclusiveMin interface{}   `yaml:"exclusiveMinimum,omitempty" json:"exclusiveMinimum,omitempty"` // bool in 3.0, number in 3.1
+	ExclusiveMax interface{}   `yaml:"exclusiveMaximum,omitempty" json:"exclusiveMaximum,omitempty"` // bool in 3.0, number in 3.1
+	MultipleOf   float64       `yaml:"multipleOf,omitempty"       json:"multipleOf,omitempty"`
+	XMLRepr      *swagXMLRepr  `yaml:"xml,omitempty"              json:"xml,omitempty"`
+
+	Discriminator *oas3Discriminator `yaml:"discriminator,omitempty" json:"discriminator,omitempty"`
+
+	EnumVarNames []string `yaml:"x-enum-varnames,omitempty"     json:"x-enum-varnames,omitempty"`
+	EnumDescs    []string `yaml:"x-enum-descriptions,omitempty" json:"x-enum-descriptions,omitempty"`
+
+	Items                *oas3Schema   `yaml:"items,omitempty"                json:"items,omitempty"`
+	Properties           *orderedMap   `yaml:"properties,omitempty"           json:"properties,omitempty"` // map[string]*oas3Schema
+	AdditionalProperties *oas3Schema   `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	AllOf                []*oas3Schema `yaml:"allOf,omitempty"                json:"allOf,omitempty"`
+	OneOf                []*oas3Schema `yaml:"oneOf,omitempty"                json:"oneOf,omitempty"`
+	AnyOf                []*oas3Schema `yaml:"anyOf,omitempty"                json:"anyOf,omitempty"`
+	OriginRef            string        `yaml:"-"                              json:"-"`
+	Ref                  string        `yaml:"$ref,omitempty"                 json:"$ref,omitempty"`
+}
+
+type oas3Discriminator struct {
+	PropertyName string            `yaml:"propertyName"      json:"propertyName"`
+	Mapping      map[string]string `yaml:"mapping,omitempty" json:"mapping,omitempty"`
+}
+
+// ==========================
+// schema & items & mediaType
+// ==========================
+
+func buildOas3Items(arr *apiArray, opt *ItemOption) (*oas3Schema, error) {
+	/*
+		"items": {
+		  "type": "integer",
+		  "format": "int64",
+		  // ...
+		}
+		"items": {
+		  "type": "array",
+		  "items": {},
+		  // ...
+		}
+		"items": {
+		  "$ref": "#/components/schemas/User"
+		}
+		"items": {
+		  "type": "object",
+		  "additionalProperties": {},
+		  // ...
+		}
+	*/
+	var items *oas3Schema
+	if opt == nil {
+		items = &oas3Schema{}
+	} else {
+		items = &oas3Schema{
+			Default:      opt.defaul,
+			Example:      opt.example,
+			Pattern:      opt.pattern,
+			Enum:         opt.enum,
+			MaxLength:    opt.maxLength,
+			MinLength:    opt.minLength,
+			MaxItems:     opt.maxItems,
+			MinItems:     opt.minItems,
+			UniqueItems:  opt.uniqueItems,
+			Maximum:      opt.maximum,
+			Minimum:      opt.minimum,
+			ExclusiveMin: buildOas3Exclusive(opt.exclusiveMin),
+			ExclusiveMax: buildOas3Exclusive(opt.exclusiveMax),
+			MultipleOf:   opt.multipleOf,
+			XMLRepr:      buildSwagXMLRepr(opt.xmlRepr),
+		}
+	}
+
+	switch arr.item.kind {
+	case apiPrimeKind:
+		prime := arr.item.prime
+		if prime.typ == FILE {
+			return nil, newDocumentError("Invalid file type used in non-request parameter")
+		}
+		items.Type = prime.typ
+		items.Format = prime.format
+		return items, nil
+	case apiArrayKind:
+		items.Type = ARRAY
+		var o *ItemOption
+		if opt != nil {
+			o = opt.itemOption
+		}
+		var err error
+		items.Items, err = buildOas3Items(arr.item.array, o)
+		if err != nil {
+			return nil, err
+		}
+		return items, nil
+	case apiObjectKind:
+		origin := arr.item.name
+		ref := "#/components/schemas/" + origin
+		return &oas3Schema{OriginRef: origin, Ref: ref}, nil
+	case apiMapKind:
+		items.Type = OBJECT
+		var o *ItemOption
+		if opt != nil {
+			o = opt.itemOption
+		}
+		var err error
+		items.AdditionalProperties, err = buildOas3Items(&apiArray{item: arr.item.mapp.value}, o)
+		if err != nil {
+			return nil, err
+		}
+		return items, nil
+	case apiUnionKind:
+		if err := buildOas3Union(items, arr.item.union); err != nil {
+			return nil, err
+		}
+		return items, nil
+	default:
+		return nil, nil // unreachable
+	}
+}
+
+func buildOas3Schema(typ string, option *ItemOption, allowFile bool) (*oas3Schema, error) {
+	/*
+		{
+		  "type": "string",
+		  "format": "password"
+		}
+		{
+		  "type": "array",
+		  "items": {}
+		},
+		{
+		  "$ref": "#/components/schemas/User"
+		}
+		{
+		  "type": "object",
+		  "additionalProperties": {}
+		}
+	*/
+	at, err := parseApiType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	switch at.kind {
+	case apiPrimeKind:
+		if at.prime.typ == FILE {
+			if !allowFile {
+				return nil, newDocumentError("Invalid file type used in non-request parameter")
+			}
+			return &oas3Schema{Type: STRING, Format: BINARY}, nil // file -> string#binary
+		}
+		return &oas3Schema{Type: at.prime.typ, Format: at.prime.format}, nil
+	case apiArrayKind:
+		items, err := buildOas3Items(at.array, option)
+		if err != nil {
+			return nil, err
+		}
+		return &oas3Schema{Type: ARRAY, Items: items}, nil
+	case apiObjectKind:
+		return &oas3Schema{OriginRef: at.name, Ref: "#/components/schemas/" + at.name}, nil
+	case apiMapKind:
+		value, err := buildOas3Items(&apiArray{item: at.mapp.value}, option) // value's option
+		if err != nil {
+			return nil, err
+		}
+		return &oas3Schema{Type: OBJECT, AdditionalProperties: value}, nil
+	case apiUnionKind:
+		schema := &oas3Schema{}
+		if err := buildOas3Union(schema, at.union); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	default:
+		return nil, nil // unreachable
+	}
+}
+
+func buildOas3Union(schema *oas3Schema, union *apiUnion) error {
+	/*
+		{
+		  "oneOf": [{}, {}]
+		}
+		{
+		  "anyOf": [{}, {}]
+		}
+	*/
+	items := make([]*oas3Schema, 0, len(union.items))
+	for _, item := range union.items {
+		s, err := buildOas3Items(&apiArray{item: item}, nil)
+		if err != nil {
+			return err
+		}
+		items = append(items, s)
+	}
+	if union.typ == ANYOF {
+		schema.AnyOf = items
+	} else {
+		schema.OneOf = items
+	}
+	return nil
+}
+
+func buildOas3InlineSchema(typ string, inline *InlineObject) (*oas3Schema, error) {
+	/*
+		{
+		  "type": "object",
+		  "required": [],
+		  "properties": {}
+		}
+		{
+		  "type": "array",
+		  "items": {
+		    "type": "object",
+		    // ...
+		  }
+		}
+	*/
+	required, properties, err := buildOas3Properties(inline.properties)
+	if err != nil {
+		return nil, err
+	}
+	schema := &oas3Schema{Type: OBJECT, Required: required, Properties: properties}
+	for i := inlineObjectDepth(typ); i > 0; i-- {
+		schema = &oas3Schema{Type: ARRAY, Items: schema}
+	}
+	return schema, nil
+}
+
+func buildOas3SchemaOptions(schema *oas3Schema, defaul, example interface{}, pattern string, enum []interface{}, maxLength, minLength, maxItems, minItems *int,
+	uniqueItems bool, maximum, minimum *float64, exclusiveMin, exclusiveMax bool, multipleOf float64, xmlRepr *XMLRepr) {
+	schema.Default = defaul
+	schema.Example = example
+	schema.Pattern = pattern
+	schema.Enum = enum
+	schema.MaxLength = maxLength
+	schema.MinLength = minLength
+	schema.MaxItems = maxItems
+	schema.MinItems = minItems
+	schema.UniqueItems = uniqueItems
+	schema.Maximum = maximum
+	schema.Minimum = minimum
+	schema.ExclusiveMin = buildOas3Exclusive(exclusiveMin)
+	schema.ExclusiveMax = buildOas3Exclusive(exclusiveMax)
+	schema.MultipleOf = multipleOf
+	schema.XMLRepr = buildSwagXMLRepr(xmlRepr)
+}
+
+func buildOas3Exclusive(exclusive bool) interface{} {
+	if !exclusive {
+		return nil // omit false value
+	}
+	return true
+}
+
+func buildOas3Style(collectionFormat string) (style string, explode *bool) {
+	no, yes := false, true
+	switch collectionFormat {
+	case CSV:
+		return "form", &no
+	case SSV:
+		return "spaceDelimited", &no
+	case PIPES:
+		return "pipeDelimited", &no
+	case MULTI:
+		return "form", &yes
+	}
+	return "", nil // tsv is not supported in OpenAPI 3
+}
+
+func buildOas3Servers(host, basePath string, schemes []string) []*oas3Server {
+	if len(schemes) == 0 {
+		return []*oas3Server{{Url: "//" + host + basePath}} // scheme-relative url
+	}
+	out := make([]*oas3Server, 0, len(schemes))
+	for _, scheme := range schemes {
+		out = append(out, &oas3Server{Url: scheme + "://" + host + basePath})
+	}
+	return out
+}
+
+// ===============================
+// params & responses & definition
+// ===============================
+
+func buildOas3Params(params []*Param, consumes []string, enums map[string]*Definition) ([]*oas3Param, *oas3RequestBody, error) {
+	out := make([]*oas3Param, 0, len(params))
+	var body *Param
+	forms := make([]*Param, 0)
+	for _, p := range params {
+		switch p.in {
+		case BODY:
+			body = p
+			continue
+		case FORM:
+			forms = append(forms, p)
+			continue
+		}
+
+		// parameter without body and form
+		schema, err := buildOas3Schema(p.typ, p.itemOption, false)
+		if err == nil && ((schema.Ref != "" && enums[schema.OriginRef] == nil) || schema.Type == OBJECT || schema.AdditionalProperties != nil || schema.OneOf != nil || schema.AnyOf != nil) {
+			err = newDocumentError("Invalid type `" + p.typ + "` used in non-body parameter") // only allowed primitive, array and enum
+		}
+		if err != nil {
+			return nil, nil, errorInField(err, p.name)
+		}
+		if schema.Ref == "" {
+			buildOas3SchemaOptions(schema, p.defaul, nil, p.pattern, p.enum, p.maxLength, p.minLength, p.maxItems, p.minItems,
+				p.uniqueItems, p.maximum, p.minimum, p.exclusiveMin, p.exclusiveMax, p.multipleOf, p.xmlRepr) // example is put in parameter
+		}
+		param := &oas3Param{
+			Name:        p.name,
+			In:          p.in,
+			Required:    p.required,
+			Description: p.desc,
+			AllowEmpty:  p.allowEmpty,
+			Example:     p.example,
+			Schema:      schema,
+		}
+		if schema.Type == ARRAY {
+			param.Style, param.Explode = buildOas3Style(p.collectionFormat)
+		}
+		out = append(out, param)
+	}
+
+	// request body, body param first
+	if body != nil {
+		var schema *oas3Schema
+		var err error
+		if body.inlineObject != nil {
+			schema, err = buildOas3InlineSchema(body.typ, body.inlineObject)
+		} else {
+			schema, err = buildOas3Schema(body.typ, body.itemOption, false)
+		}
+		if err != nil {
+			return nil, nil, errorInField(err, body.name)
+		}
+		if schema.Ref == "" {
+			buildOas3SchemaOptions(schema, body.defaul, body.example, body.pattern, body.enum, body.maxLength, body.minLength, body.maxItems, body.minItems,
+				body.uniqueItems, body.maximum, body.minimum, body.exclusiveMin, body.exclusiveMax, body.multipleOf, body.xmlRepr)
+		}
+		content := make(map[string]*oas3MediaType, len(consumes))
+		for _, mime := range consumes {
+			content[mime] = &oas3MediaType{Schema: schema}
+		}
+		return out, &oas3RequestBody{Description: body.desc, Required: body.required, Content: content}, nil
+	}
+	if len(forms) == 0 {
+		return out, nil, nil
+	}
+
+	// request body, form params
+	required := make([]string, 0, len(forms))
+	properties := newOrderedMap(len(forms)) // map[string]*oas3Schema
+	hasFile := false
+	for _, p := range forms {
+		if p.required {
+			required = append(required, p.name)
+		}
+		schema, err := buildOas3Schema(p.typ, p.itemOption, true)
+		if err == nil && ((schema.Ref != "" && enums[schema.OriginRef] == nil) || schema.AdditionalProperties != nil || schema.OneOf != nil || schema.AnyOf != nil) {
+			err = newDocumentError("Invalid type `" + p.typ + "` used in non-body parameter") // only allowed primitive, array and enum
+		}
+		if err != nil {
+			return nil, nil, errorInField(err, p.name)
+		}
+		if schema.Format == BINARY {
+			hasFile = true
+		}
+		if schema.Ref == "" {
+			schema.Description = p.desc
+			buildOas3SchemaOptions(schema, p.defaul, p.example, p.pattern, p.enum, p.maxLength, p.minLength, p.maxItems, p.minItems,
+				p.uniqueItems, p.maximum, p.minimum, p.exclusiveMin, p.exclusiveMax, p.multipleOf, p.xmlRepr)
+		}
+		properties.Set(p.name, schema)
+	}
+	formSchema := &oas3Schema{Type: OBJECT, Required: required, Properties: properties}
+	content := make(map[string]*oas3MediaType, 1)
+	for _, mime := range consumes {
+		if mime == MPFD || mime == URL {
+			content[mime] = &oas3MediaType{Schema: formSchema}
+		}
+	}
+	if len(content) == 0 {
+		if hasFile {
+			content[MPFD] = &oas3MediaType{Schema: formSchema}
+		} else {
+			content[URL] = &oas3MediaType{Schema: formSchema}
+		}
+	}
+	return out, &oas3RequestBody{Required: len(required) > 0, Content: content}, nil
+}
+
+func buildOas3Responses(responses []*Response, produces []string) (map[string]*oas3Response, error) {
+	out := make(map[string]*oas3Response, len(responses))
+	for _, r := range responses {
+		desc := r.desc
+		if desc == "" {
+			desc = strconv.Itoa(r.code) + " " + http.StatusText(r.code)
+		}
+		headers := make(map[string]*oas3Header, len(r.headers))
+		for _, h := range r.headers {
+			schema, err := buildOas3Schema(h.typ, nil, false)
+			if err == nil && (schema.Ref != "" || schema.Type == OBJECT || schema.Items != nil || schema.AdditionalProperties != nil || schema.OneOf != nil || schema.AnyOf != nil) {
+				err = newDocumentError("Invalid type `" + h.typ + "` used in response header") // only allow primitive
+			}
+			if err != nil {
+				return nil, errorInField(err, h.name)
+			}
+			headers[h.name] = &oas3Header{Description: h.desc, Example: h.example, Schema: schema}
+		}
+
+		content := make(map[string]*oas3MediaType, len(produces))
+		var schema *oas3Schema
+		if r.inlineObject != nil || r.typ != "" {
+			var err error
+			if r.inlineObject != nil {
+				schema, err = buildOas3InlineSchema(r.typ, r.inlineObject)
+			} else {
+				schema, err = buildOas3Schema(r.typ, nil, false)
+			}
+			if err != nil {
+				return nil, errorInField(err, strconv.Itoa(r.code))
+			}
+			for _, mime := range produces {
+				content[mime] = &oas3MediaType{Schema: schema}
+			}
+		}
+		for _, e := range r.examples {
+			if mt, ok := content[e.mime]; ok {
+				mt.Example = e.example
+			} else {
+				content[e.mime] = &oas3MediaType{Schema: schema, Example: e.example}
+			}
+		}
+
+		out[strconv.Itoa(r.code)] = &oas3Response{
+			Description: desc,
+			Headers:     headers,
+			Content:     content,
+		}
+	}
+	return out, nil
+}
+
+func buildOas3Discriminator(propertyName string, mapping map[string]string) *oas3Discriminator {
+	if propertyName == "" {
+		return nil
+	}
+	out := &oas3Discriminator{PropertyName: propertyName, Mapping: make(map[string]string, len(mapping))}
+	for value, name := range mapping {
+		out.Mapping[value] = "#/components/schemas/" + name
+	}
+	return out
+}
+
+func buildOas3Properties(properties []*Property) ([]string, *orderedMap, error) {
+	required := make([]string, 0, len(properties)/2)
+	out := newOrderedMap(len(properties)) // map[string]*oas3Schema
+	for _, p := range properties {
+		if p.required {
+			required = append(required, p.name)
+		}
+		if p.inlineObject != nil {
+			schema, err := buildOas3InlineSchema(p.typ, p.inlineObject)
+			if err != nil {
+				return nil, nil, errorInField(err, p.name)
+			}
+			schema.Description = p.desc
+			out.Set(p.name, buildOas3Modifiers(schema, p))
+			continue
+		}
+		schema, err := buildOas3Schema(p.typ, p.itemOption, false)
+		if err != nil {
+			return nil, nil, errorInField(err, p.name)
+		}
+		if schema.Ref == "" {
+			schema.Description = p.desc
+			buildOas3SchemaOptions(schema, p.defaul, p.example, p.pattern, p.enum, p.maxLength, p.minLength, p.maxItems, p.minItems,
+				p.uniqueItems, p.maximum, p.minimum, p.exclusiveMin, p.exclusiveMax, p.multipleOf, p.xmlRepr)
+		}
+		out.Set(p.name, buildOas3Modifiers(schema, p))
+	}
+	return required, out, nil
+}
+
+// buildOas3Modifiers sets the nullable, readOnly and writeOnly of given property to schema, notes that $ref schema will be wrapped in
+// allOf, because all the siblings of $ref are ignored.
+func buildOas3Modifiers(schema *oas3Schema, p *Property) *oas3Schema {
+	if !p.nullable && !p.readOnly && !p.writeOnly {
+		return schema
+	}
+	if schema.Ref != "" {
+		schema = &oas3Schema{AllOf: []*oas3Schema{schema}}
+	}
+	schema.Nullable, schema.ReadOnly, schema.WriteOnly = p.nullable, p.readOnly, p.writeOnly
+	return schema
+}
+
+func buildOas3Definition(definition *Definition) (*oas3Schema, error) {
+	if definition.enumType != "" {
+		at, err := parseApiType(definition.enumType)
+		if err != nil {
+			return nil, err
+		}
+		return &oas3Schema{
+			Type:         at.prime.typ, // enum type has been checked
+			Format:       at.prime.format,
+			Description:  definition.desc,
+			XMLRepr:      buildSwagXMLRepr(definition.xmlRepr),
+			Enum:         definition.enums,
+			EnumVarNames: definition.enumVarNames,
+			EnumDescs:    definition.enumDescs,
+		}, nil
+	}
+
+	required, properties, err := buildOas3Properties(definition.properties)
+	if err != nil {
+		return nil, err
+	}
+	allOf := make([]*oas3Schema, 0, len(definition.extends))
+	for _, ext := range definition.extends {
+		allOf = append(allOf, &oas3Schema{OriginRef: ext, Ref: "#/components/schemas/" + ext})
+	}
+
+	return &oas3Schema{
+		Type:          OBJECT, // fixed schema type to object
+		Required:      required,
+		Description:   definition.desc,
+		XMLRepr:       buildSwagXMLRepr(definition.xmlRepr),
+		Properties:    properties,
+		AllOf:         allOf,
+		Discriminator: buildOas3Discriminator(definition.discriminator, definition.mapping),
+	}, nil
+}
+
+// ========================
+// operations & definitions
+// ========================
+
+func buildOas3Operations(doc *Document) (map[string]map[string]*oas3Operation, error) {
+	var globalParams []*Param
+	consumes, produces := []string{JSON}, []string{JSON}
+	if opt := doc.option; opt != nil {
+		globalParams = opt.globalParams
+		if len(opt.consumes) > 0 {
+			consumes = opt.consumes
+		}
+		if len(opt.produces) > 0 {
+			produces = opt.produces
+		}
+	}
+
+	// route - method - operation
+	out := make(map[string]map[string]*oas3Operation, 2) // cap defaults to 2
+	enums := collectEnumDefinitions(doc.definitions)
+	naming := genericNamingOf(doc)
+	for _, op := range doc.operations {
+		method := strings.ToLower(op.method)
+		operationId := operationIdOf(op)
+		securities := make([]map[string][]string, 0, len(op.securities))
+		for _, s := range op.securities {
+			secReq := map[string][]string{s: {}}
+			if scopes, ok := op.secsScopes[s]; ok {
+				secReq[s] = scopes
+			}
+			securities = append(securities, secReq)
+		}
+		params := operationParams(op, globalParams)
+		opConsumes, opProduces := consumes, produces
+		if len(op.consumes) > 0 {
+			opConsumes = op.consumes
+		}
+		if len(op.produces) > 0 {
+			opProduces = op.produces
+		}
+		var servers []*oas3Server
+		if len(op.schemes) > 0 {
+			servers = buildOas3Servers(doc.host, doc.basePath, op.schemes)
+		}
+		parameters, requestBody, err := buildOas3Params(nameGenericParams(params, naming), opConsumes, enums)
+		if err != nil {
+			return nil, errorInOperation(err, op)
+		}
+		responses, err := buildOas3Responses(nameGenericResponses(op.responses, naming), opProduces)
+		if err != nil {
+			return nil, errorInOperation(err, op)
+		}
+
+		_, ok := out[op.route]
+		if !ok {
+			out[op.route] = make(map[string]*oas3Operation, 4) // cap defaults to 4
+		}
+		out[op.route][method] = &oas3Operation{
+			Summary:     op.summary,
+			OperationId: operationId,
+			Description: op.desc,
+			Servers:     servers,
+			Tags:        op.tags,
+			Securities:  securities,
+			Deprecated:  op.deprecated,
+			ExternalDoc: buildSwagExternalDoc(op.externalDoc),
+			Parameters:  parameters,
+			RequestBody: requestBody,
+			Responses:   responses,
+		}
+	}
+	return out, nil
+}
+
+func buildOas3Definitions(doc *Document) (map[string]*oas3Schema, error) {
+	// prehandle definition list
+	newDefinitionList, err := prehandleAllDefinitions(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// return result map
+	out := make(map[string]*oas3Schema, len(newDefinitionList))
+	for _, definition := range newDefinitionList {
+		def, err := buildOas3Definition(definition)
+		if err != nil {
+			return nil, errorInDefinition(err, definition.name)
+		}
+		out[definition.name] = def
+	}
+	return out, nil
+}
+
+func buildOas3Securities(securities []*Security) map[string]*oas3Security {
+	out := make(map[string]*oas3Security, len(securities))
+	for _, s := range securities {
+		if s.typ == APIKEY {
+			out[s.title] = &oas3Security{Type: APIKEY, Description: s.desc, Name: s.name, In: s.in}
+		} else if s.typ == BASIC {
+			out[s.title] = &oas3Security{Type: "http", Description: s.desc, Scheme: BASIC}
+		} else if s.typ == OAUTH2 {
+			scopes := make(map[string]string, len(s.scopes))
+			for _, c := range s.scopes {
+				scopes[c.scope] = c.desc
+			}
+			flow := &oas3OAuthFlow{AuthorizationUrl: s.authorizationUrl, TokenUrl: s.tokenUrl, Scopes: scopes}
+			flows := &oas3OAuthFlows{}
+			switch s.flow {
+			case IMPLICIT_FLOW:
+				flows.Implicit = flow
+			case PASSWORD_FLOW:
+				flows.Password = flow
+			case APPLICATION_FLOW:
+				flows.ClientCredentials = flow
+			case ACCESSCODE_FLOW:
+				flows.AuthorizationCode = flow
+			}
+			out[s.title] = &oas3Security{Type: OAUTH2, Description: s.desc, Flows: flows}
+		}
+	}
+	return out
+}
+
+// ========
+// document
+// ========
+
+func buildOas3Document(doc *Document) (*oas3Document, error) {
+	// check
+	if err := checkDocument(doc); err != nil {
+		return nil, err
+	}
+	doc, err := fillDocumentExamples(doc, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// info
+	out := &oas3Document{
+		OpenAPI: "3.0.3",
+		Info: &swagInfo{
+			Title:          doc.info.title,
+			Version:        doc.info.version,
+			Description:    doc.info.desc,
+			TermsOfService: doc.info.termsOfService,
+		},
+		Components: &oas3Components{},
+	}
+	if doc.info.license != nil {
+		out.Info.License = &swagLicense{Name: doc.info.license.name, Url: doc.info.license.url}
+	}
+	if doc.info.contact != nil {
+		out.Info.Contact = &swagContact{Name: doc.info.contact.name, Url: doc.info.contact.url, Email: doc.info.contact.email}
+	}
+
+	// option
+	var schemes []string
+	if opt := doc.option; opt != nil {
+		tags := make([]*swagTag, 0, len(opt.tags))
+		for _, t := range opt.tags {
+			tags = append(tags, &swagTag{Name: t.name, Description: t.desc, ExternalDoc: buildSwagExternalDoc(t.externalDoc)})
+		}
+		schemes = opt.schemes
+		out.Tags = tags
+		out.ExternalDoc = buildSwagExternalDoc(opt.externalDoc)
+		out.Components.Securities = buildOas3Securities(opt.securities)
+	}
+	out.Servers = buildOas3Servers(doc.host, doc.basePath, schemes)
+
+	// definitions & operations
+	out.Components.Schemas, err = buildOas3Definitions(doc)
+	if err != nil {
+		return nil, err
+	}
+	out.Operations, err = buildOas3Operations(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ===========
+// openapi 3.1
+// ===========
+
+func walkOas3Schemas(doc *oas3Document, fn func(schema *oas3Schema)) {
+	visited := make(map[*oas3Schema]bool)
+	var walkFn func(schema *oas3Schema)
+	walkFn = func(schema *oas3Schema) {
+		if schema == nil || visited[schema] {
+			return
+		}
+		visited[schema] = true
+		fn(schema)
+		walkFn(schema.Items)
+		walkFn(schema.AdditionalProperties)
+		for _, subs := range [][]*oas3Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+			for _, sub := range subs {
+				walkFn(sub)
+			}
+		}
+		if schema.Properties != nil {
+			for _, key := range schema.Properties.Keys() {
+				walkFn(schema.Properties.MustGet(key).(*oas3Schema))
+			}
+		}
+	}
+
+	if doc.Components != nil {
+		for _, schema := range doc.Components.Schemas {
+			walkFn(schema)
+		}
+	}
+	for _, methods := range doc.Operations {
+		for _, op := range methods {
+			for _, param := range op.Parameters {
+				walkFn(param.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					walkFn(mt.Schema)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, header := range resp.Headers {
+					walkFn(header.Schema)
+				}
+				for _, mt := range resp.Content {
+					walkFn(mt.Schema)
+				}
+			}
+		}
+	}
+}
+
+func buildOas31Document(doc *Document) (*oas3Document, error) {
+	out, err := buildOas3Document(doc)
+	if err != nil {
+		return nil, err
+	}
+	out.OpenAPI = "3.1.0"
+	out.Dialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+	// convert to json schema 2020-12
+	walkOas3Schemas(out, func(schema *oas3Schema) {
+		if schema.Nullable {
+			schema.Nullable = false
+			null := &oas3Schema{Type: "null"}
+			switch {
+			case schema.Type != nil:
+				schema.Type = []interface{}{schema.Type, "null"} // T -> [T, "null"]
+			case schema.OneOf != nil:
+				schema.OneOf = append(schema.OneOf, null) // oneOf: [...] -> oneOf: [..., null]
+			case schema.AnyOf != nil:
+				schema.AnyOf = append(schema.AnyOf, null) // anyOf: [...] -> anyOf: [..., null]
+			case len(schema.AllOf) == 1:
+				schema.AnyOf, schema.AllOf = []*oas3Schema{schema.AllOf[0], null}, nil // allOf: [$ref] -> anyOf: [$ref, null]
+			}
+		}
+		if schema.ExclusiveMin == true {
+			schema.ExclusiveMin = nil
+			if schema.Minimum != nil {
+				schema.ExclusiveMin, schema.Minimum = *schema.Minimum, nil // minimum -> exclusiveMinimum
+			}
+		}
+		if schema.ExclusiveMax == true {
+			schema.ExclusiveMax = nil
+			if schema.Maximum != nil {
+				schema.ExclusiveMax, schema.Maximum = *schema.Maximum, nil // maximum -> exclusiveMaximum
+			}
+		}
+		if schema.Example != nil {
+			schema.Examples, schema.Example = []interface{}{schema.Example}, nil // example -> examples
+		}
+	})
+	return out, nil
+}
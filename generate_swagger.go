@@ -122,19 +122,29 @@ type swagResponseHeader struct {
 }
 
 type swagResponseSchema struct {
-	Type      string     `yaml:"type,omitempty"   json:"type,omitempty"`
-	Format    string     `yaml:"format,omitempty" json:"format,omitempty"`
-	Items     *swagItems `yaml:"items,omitempty"  json:"items,omitempty"`
-	OriginRef string     `yaml:"-"                json:"-"`
-	Ref       string     `yaml:"$ref,omitempty"   json:"$ref,omitempty"`
+	Type                 string      `yaml:"type,omitempty"                 json:"type,omitempty"`
+	Format               string      `yaml:"format,omitempty"               json:"format,omitempty"`
+	Required             []string    `yaml:"required,omitempty"             json:"required,omitempty"`
+	Items                *swagItems  `yaml:"items,omitempty"                json:"items,omitempty"`
+	Properties           *orderedMap `yaml:"properties,omitempty"           json:"properties,omitempty"` // map[string]*swagSchema
+	AdditionalProperties *swagItems  `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	OriginRef            string      `yaml:"-"                              json:"-"`
+	Ref                  string      `yaml:"$ref,omitempty"                 json:"$ref,omitempty"`
 }
 
 type swagDefinition struct {
-	Type        string       `yaml:"type"                  json:"type"`
-	Required    []string     `yaml:"required,omitempty"    json:"required,omitempty"`
-	Description string       `yaml:"description,omitempty" json:"description,omitempty"`
-	XMLRepr     *swagXMLRepr `yaml:"xml,omitempty"         json:"xml,omitempty"`
-	Properties  *orderedMap  `yaml:"properties,omitempty"  json:"properties,omitempty"` // map[string]*swagSchema
+	Type          string        `yaml:"type"                    json:"type"`
+	Format        string        `yaml:"format,omitempty"        json:"format,omitempty"`
+	Required      []string      `yaml:"required,omitempty"      json:"required,omitempty"`
+	Description   string        `yaml:"description,omitempty"   json:"description,omitempty"`
+	Discriminator string        `yaml:"discriminator,omitempty" json:"discriminator,omitempty"`
+	XMLRepr       *swagXMLRepr  `yaml:"xml,omitempty"           json:"xml,omitempty"`
+	AllOf         []*swagSchema `yaml:"allOf,omitempty"         json:"allOf,omitempty"`
+	Properties    *orderedMap   `yaml:"properties,omitempty"    json:"properties,omitempty"` // map[string]*swagSchema
+
+	Enum         []interface{} `yaml:"enum,omitempty"                json:"enum,omitempty"`
+	EnumVarNames []string      `yaml:"x-enum-varnames,omitempty"     json:"x-enum-varnames,omitempty"`
+	EnumDescs    []string      `yaml:"x-enum-descriptions,omitempty" json:"x-enum-descriptions,omitempty"`
 }
 
 type swagXMLRepr struct {
@@ -148,8 +158,10 @@ type swagXMLRepr struct {
 type swagSchema struct {
 	Type             string        `yaml:"type,omitempty"             json:"type,omitempty"`
 	Format           string        `yaml:"format,omitempty"           json:"format,omitempty"`
-	Required         bool          `yaml:"required,omitempty"         json:"required,omitempty"` // <<<
+	Required         []string      `yaml:"required,omitempty"         json:"required,omitempty"`
 	Description      string        `yaml:"description,omitempty"      json:"description,omitempty"`
+	ReadOnly         bool          `yaml:"readOnly,omitempty"         json:"readOnly,omitempty"`
+	Nullable         bool          `yaml:"x-nullable,omitempty"       json:"x-nullable,omitempty"`
 	AllowEmpty       bool          `yaml:"allowEmptyValue,omitempty"  json:"allowEmptyValue,omitempty"` // ?
 	Default          interface{}   `yaml:"default,omitempty"          json:"default,omitempty"`
 	Example          interface{}   `yaml:"example,omitempty"          json:"example,omitempty"`
@@ -168,14 +180,17 @@ type swagSchema struct {
 	MultipleOf       float64       `yaml:"multipleOf,omitempty"       json:"multipleOf,omitempty"`
 	XMLRepr          *swagXMLRepr  `yaml:"xml,omitempty"              json:"xml,omitempty"`
 
-	Items     *swagItems `yaml:"items,omitempty" json:"items,omitempty"`
-	OriginRef string     `yaml:"-"               json:"-"`
-	Ref       string     `yaml:"$ref,omitempty"  json:"$ref,omitempty"`
+	Items                *swagItems  `yaml:"items,omitempty"                json:"items,omitempty"`
+	Properties           *orderedMap `yaml:"properties,omitempty"           json:"properties,omitempty"` // map[string]*swagSchema
+	AdditionalProperties *swagItems  `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	OriginRef            string      `yaml:"-"                              json:"-"`
+	Ref                  string      `yaml:"$ref,omitempty"                 json:"$ref,omitempty"`
 }
 
 type swagItems struct {
 	Type             string        `yaml:"type,omitempty"             json:"type,omitempty"`
 	Format           string        `yaml:"format,omitempty"           json:"format,omitempty"`
+	Required         []string      `yaml:"required,omitempty"         json:"required,omitempty"`
 	AllowEmpty       bool          `yaml:"allowEmptyValue,omitempty"  json:"allowEmptyValue,omitempty"` // ?
 	Default          interface{}   `yaml:"default,omitempty"          json:"default,omitempty"`
 	Example          interface{}   `yaml:"example,omitempty"          json:"example,omitempty"` // ?
@@ -194,16 +209,18 @@ type swagItems struct {
 	MultipleOf       float64       `yaml:"multipleOf,omitempty"       json:"multipleOf,omitempty"`
 	XMLRepr          *swagXMLRepr  `yaml:"xml,omitempty"              json:"xml,omitempty"`
 
-	Items     *swagItems `yaml:"items,omitempty" json:"items,omitempty"`
-	OriginRef string     `yaml:"-"               json:"-"`
-	Ref       string     `yaml:"$ref,omitempty"  json:"$ref,omitempty"`
+	Items                *swagItems  `yaml:"items,omitempty"                json:"items,omitempty"`
+	Properties           *orderedMap `yaml:"properties,omitempty"           json:"properties,omitempty"` // map[string]*swagSchema
+	AdditionalProperties *swagItems  `yaml:"additionalProperties,omitempty" json:"additionalProperties,omitempty"`
+	OriginRef            string      `yaml:"-"                              json:"-"`
+	Ref                  string      `yaml:"$ref,omitempty"                 json:"$ref,omitempty"`
 }
 
 // ======================================
 // items & schema & externalDoc & xmlRepr
 // ======================================
 
-func buildSwagItems(arr *apiArray, opt *ItemOption) *swagItems {
+func buildSwagItems(arr *apiArray, opt *ItemOption) (*swagItems, error) {
 	/*
 		"items": {
 		  "type": "integer",
@@ -218,6 +235,11 @@ func buildSwagItems(arr *apiArray, opt *ItemOption) *swagItems {
 		"items": {
 		  "$ref": "#/definitions/User"
 		}
+		"items": {
+		  "type": "object",
+		  "additionalProperties": {},
+		  // ...
+		}
 	*/
 	var items *swagItems
 	if opt == nil {
@@ -248,29 +270,49 @@ func buildSwagItems(arr *apiArray, opt *ItemOption) *swagItems {
 	case apiPrimeKind:
 		prime := arr.item.prime
 		if prime.typ == FILE {
-			panic("Invalid file type used in non-request parameter")
+			return nil, newDocumentError("Invalid file type used in non-request parameter")
 		}
 		items.Type = prime.typ
 		items.Format = prime.format
-		return items
+		return items, nil
 	case apiArrayKind:
 		items.Type = ARRAY
 		var o *ItemOption
 		if opt != nil {
 			o = opt.itemOption
 		}
-		items.Items = buildSwagItems(arr.item.array, o)
-		return items
+		var err error
+		items.Items, err = buildSwagItems(arr.item.array, o)
+		if err != nil {
+			return nil, err
+		}
+		return items, nil
 	case apiObjectKind:
 		origin := arr.item.name
 		ref := "#/definitions/" + origin
-		return &swagItems{OriginRef: origin, Ref: ref}
+		return &swagItems{OriginRef: origin, Ref: ref}, nil
+	case apiMapKind:
+		items.Type = OBJECT
+		var o *ItemOption
+		if opt != nil {
+			o = opt.itemOption
+		}
+		var err error
+		items.AdditionalProperties, err = buildSwagItems(&apiArray{item: arr.item.mapp.value}, o)
+		if err != nil {
+			return nil, err
+		}
+		return items, nil
+	case apiUnionKind:
+		logWarning("Union type `" + arr.item.name + "` is not supported by swagger 2, it is generated as object")
+		items.Type = OBJECT
+		return items, nil
 	default:
-		return nil // unreachable
+		return nil, nil // unreachable
 	}
 }
 
-func buildSwagSchema(typ string, option *ItemOption, allowFile bool) (outType, outFmt, origin, ref string, items *swagItems) {
+func buildSwagSchema(typ string, option *ItemOption, allowFile bool) (outType, outFmt, origin, ref string, items, additional *swagItems, err error) {
 	/*
 		{
 		  "type": "string",
@@ -285,30 +327,73 @@ func buildSwagSchema(typ string, option *ItemOption, allowFile bool) (outType, o
 		{
 		  "$ref": "#/definitions/User"
 		}
+		{
+		  "type": "object",
+		  "additionalProperties": {},
+		  // ...
+		}
 	*/
-	at := parseApiType(typ)
+	at, err := parseApiType(typ)
+	if err != nil {
+		return
+	}
 
 	switch at.kind {
 	case apiPrimeKind:
 		if at.prime.typ == FILE && !allowFile {
-			panic("Invalid file type used in non-request parameter")
+			err = newDocumentError("Invalid file type used in non-request parameter")
+			return
 		}
 		outType = at.prime.typ
 		outFmt = at.prime.format
 		return
 	case apiArrayKind:
 		outType = ARRAY
-		items = buildSwagItems(at.array, option)
+		items, err = buildSwagItems(at.array, option)
 		return
 	case apiObjectKind:
 		origin = at.name
 		ref = "#/definitions/" + origin // ref
 		return
+	case apiMapKind:
+		outType = OBJECT
+		additional, err = buildSwagItems(&apiArray{item: at.mapp.value}, option) // value's option
+		return
+	case apiUnionKind:
+		logWarning("Union type `" + at.name + "` is not supported by swagger 2, it is generated as object")
+		outType = OBJECT
+		return
 	default:
 		return // unreachable
 	}
 }
 
+func buildSwagInlineItems(typ string, inline *InlineObject) (*swagItems, error) {
+	/*
+		{
+		  "type": "object",
+		  "required": [],
+		  "properties": {}
+		}
+		{
+		  "type": "array",
+		  "items": {
+		    "type": "object",
+		    // ...
+		  }
+		}
+	*/
+	required, properties, err := buildSwagProperties(inline.properties)
+	if err != nil {
+		return nil, err
+	}
+	items := &swagItems{Type: OBJECT, Required: required, Properties: properties}
+	for i := inlineObjectDepth(typ); i > 0; i-- {
+		items = &swagItems{Type: ARRAY, Items: items}
+	}
+	return items, nil
+}
+
 func buildSwagExternalDoc(doc *ExternalDoc) *swagExternalDoc {
 	if doc == nil {
 		return nil
@@ -333,15 +418,63 @@ func buildSwagXMLRepr(xml *XMLRepr) *swagXMLRepr {
 // params & responses & definition
 // ===============================
 
-func buildSwagParams(params []*Param) []*swagParam {
+// expandSwagEnumParam replaces the enum definition type of given non-body param to its primitive type and enum values, because $ref is
+// not supported in non-body parameters. Notes that only `Enum` and `Enum[]` are supported.
+func expandSwagEnumParam(p *Param, enums map[string]*Definition) *Param {
+	if p.in == BODY || p.inlineObject != nil {
+		return p
+	}
+	name, isArray := p.typ, false
+	if strings.HasSuffix(name, "[]") {
+		name, isArray = strings.TrimSpace(name[:len(name)-2]), true
+	}
+	def, ok := enums[name]
+	if !ok {
+		return p
+	}
+	out := *p
+	if !isArray {
+		out.typ, out.enum = def.enumType, def.enums
+		return &out
+	}
+	out.typ = def.enumType + "[]"
+	out.itemOption = cloneItemOption(p.itemOption)
+	if out.itemOption == nil {
+		out.itemOption = &ItemOption{}
+	}
+	out.itemOption.enum = def.enums
+	return &out
+}
+
+func buildSwagParams(params []*Param, enums map[string]*Definition) ([]*swagParam, error) {
 	out := make([]*swagParam, 0, len(params))
 	for _, p := range params {
+		p = expandSwagEnumParam(p, enums)
+		if p.inlineObject != nil {
+			// inline object, only allowed in body
+			items, err := buildSwagInlineItems(p.typ, p.inlineObject)
+			if err != nil {
+				return nil, errorInField(err, p.name)
+			}
+			out = append(out, &swagParam{
+				Name:        p.name,
+				In:          p.in,
+				Required:    p.required,
+				Description: p.desc,
+				Schema:      &swagSchema{Type: items.Type, Example: p.example, Required: items.Required, Items: items.Items, Properties: items.Properties},
+			})
+			continue
+		}
+
 		var param *swagParam
-		typ, format, origin, ref, items := buildSwagSchema(p.typ, p.itemOption, true)
+		typ, format, origin, ref, items, additional, err := buildSwagSchema(p.typ, p.itemOption, true)
+		if err != nil {
+			return nil, errorInField(err, p.name)
+		}
 		if p.in != BODY {
 			// cannot use schema
-			if ref != "" {
-				panic("Invalid type `" + p.typ + "` used in non-body parameter") // only allowed primitive and array
+			if ref != "" || typ == OBJECT {
+				return nil, errorInField(newDocumentError("Invalid type `"+p.typ+"` used in non-body parameter"), p.name) // only allowed primitive and array
 			}
 			param = &swagParam{
 				Name:             p.name,
@@ -381,36 +514,37 @@ func buildSwagParams(params []*Param) []*swagParam {
 				param.Schema = &swagSchema{OriginRef: origin, Ref: ref}
 			} else {
 				param.Schema = &swagSchema{
-					Type:             typ,
-					Format:           format,
-					AllowEmpty:       p.allowEmpty, // ?
-					Default:          p.defaul,
-					Example:          p.example,
-					Pattern:          p.pattern,
-					Enum:             p.enum,
-					MaxLength:        p.maxLength,
-					MinLength:        p.minLength,
-					MaxItems:         p.maxItems,
-					MinItems:         p.minItems,
-					UniqueItems:      p.uniqueItems,
-					CollectionFormat: p.collectionFormat, // ?
-					Maximum:          p.maximum,
-					Minimum:          p.minimum,
-					ExclusiveMin:     p.exclusiveMin,
-					ExclusiveMax:     p.exclusiveMax,
-					MultipleOf:       p.multipleOf,
-					XMLRepr:          buildSwagXMLRepr(p.xmlRepr),
-					Items:            items,
+					Type:                 typ,
+					Format:               format,
+					AllowEmpty:           p.allowEmpty, // ?
+					Default:              p.defaul,
+					Example:              p.example,
+					Pattern:              p.pattern,
+					Enum:                 p.enum,
+					MaxLength:            p.maxLength,
+					MinLength:            p.minLength,
+					MaxItems:             p.maxItems,
+					MinItems:             p.minItems,
+					UniqueItems:          p.uniqueItems,
+					CollectionFormat:     p.collectionFormat, // ?
+					Maximum:              p.maximum,
+					Minimum:              p.minimum,
+					ExclusiveMin:         p.exclusiveMin,
+					ExclusiveMax:         p.exclusiveMax,
+					MultipleOf:           p.multipleOf,
+					XMLRepr:              buildSwagXMLRepr(p.xmlRepr),
+					Items:                items,
+					AdditionalProperties: additional,
 				}
 			}
 		}
 
 		out = append(out, param)
 	}
-	return out
+	return out, nil
 }
 
-func buildSwagResponses(responses []*Response) map[string]*swagResponse {
+func buildSwagResponses(responses []*Response) (map[string]*swagResponse, error) {
 	out := make(map[string]*swagResponse, len(responses))
 	for _, r := range responses {
 		desc := r.desc
@@ -419,9 +553,12 @@ func buildSwagResponses(responses []*Response) map[string]*swagResponse {
 		}
 		headers := make(map[string]*swagResponseHeader, len(r.headers))
 		for _, h := range r.headers {
-			typ, format, _, ref, items := buildSwagSchema(h.typ, nil, false)
-			if ref != "" || items != nil {
-				panic("Invalid type `" + h.typ + "` used in response header") // only allow primitive
+			typ, format, _, ref, items, _, err := buildSwagSchema(h.typ, nil, false)
+			if err == nil && (ref != "" || items != nil || typ == OBJECT) {
+				err = newDocumentError("Invalid type `" + h.typ + "` used in response header") // only allow primitive
+			}
+			if err != nil {
+				return nil, errorInField(err, h.name)
 			}
 			headers[h.name] = &swagResponseHeader{
 				Type:        typ,
@@ -441,86 +578,145 @@ func buildSwagResponses(responses []*Response) map[string]*swagResponse {
 			Headers:     headers,
 			Examples:    examples,
 		}
-		if r.typ != "" {
-			typ, format, origin, ref, items := buildSwagSchema(r.typ, nil, false)
+		if r.inlineObject != nil {
+			items, err := buildSwagInlineItems(r.typ, r.inlineObject)
+			if err != nil {
+				return nil, errorInField(err, strconv.Itoa(r.code))
+			}
+			resp.Schema = &swagResponseSchema{Type: items.Type, Required: items.Required, Items: items.Items, Properties: items.Properties}
+		} else if r.typ != "" {
+			typ, format, origin, ref, items, additional, err := buildSwagSchema(r.typ, nil, false)
+			if err != nil {
+				return nil, errorInField(err, strconv.Itoa(r.code))
+			}
 			resp.Schema = &swagResponseSchema{
-				Type:      typ,
-				Format:    format,
-				Items:     items,
-				OriginRef: origin,
-				Ref:       ref,
+				Type:                 typ,
+				Format:               format,
+				Items:                items,
+				AdditionalProperties: additional,
+				OriginRef:            origin,
+				Ref:                  ref,
 				// ignore other fields, see https://swagger.io/specification/v2/#schemaObject
 			}
 		}
 		out[strconv.Itoa(r.code)] = resp
 	}
-	return out
+	return out, nil
 }
 
-func buildSwagDefinition(definition *Definition) *swagDefinition {
-	required := make([]string, 0, len(definition.properties)/2)
-	properties := newOrderedMap(len(definition.properties)) // map[string]*swagSchema
-	for _, p := range definition.properties {
-		if p.required {
-			required = append(required, p.name)
+func buildSwagProperties(properties []*Property) ([]string, *orderedMap, error) {
+	required := make([]string, 0, len(properties)/2)
+	out := newOrderedMap(len(properties)) // map[string]*swagSchema
+	for _, p := range properties {
+		if p.required && !p.readOnly {
+			required = append(required, p.name) // readOnly properties should not be in the required list
 		}
 
+		if p.inlineObject != nil {
+			items, err := buildSwagInlineItems(p.typ, p.inlineObject)
+			if err != nil {
+				return nil, nil, errorInField(err, p.name)
+			}
+			out.Set(p.name, &swagSchema{
+				Type:        items.Type,
+				Description: p.desc,
+				ReadOnly:    p.readOnly,
+				Nullable:    p.nullable,
+				Required:    items.Required,
+				Items:       items.Items,
+				Properties:  items.Properties,
+			})
+			continue
+		}
 		var schema *swagSchema
-		typ, format, origin, ref, items := buildSwagSchema(p.typ, p.itemOption, false)
+		typ, format, origin, ref, items, additional, err := buildSwagSchema(p.typ, p.itemOption, false)
+		if err != nil {
+			return nil, nil, errorInField(err, p.name)
+		}
 		if ref != "" {
-			schema = &swagSchema{OriginRef: origin, Ref: ref}
+			schema = &swagSchema{OriginRef: origin, Ref: ref, ReadOnly: p.readOnly, Nullable: p.nullable} // x-nullable is allowed with $ref
 		} else {
 			schema = &swagSchema{
-				// Required: p.required,
-				Type:             typ,
-				Format:           format,
-				Description:      p.desc,
-				AllowEmpty:       p.allowEmpty, // ?
-				Default:          p.defaul,
-				Example:          p.example,
-				Pattern:          p.pattern,
-				Enum:             p.enum,
-				MaxLength:        p.maxLength,
-				MinLength:        p.minLength,
-				MaxItems:         p.maxItems,
-				MinItems:         p.minItems,
-				UniqueItems:      p.uniqueItems,
-				CollectionFormat: p.collectionFormat, // ?
-				Maximum:          p.maximum,
-				Minimum:          p.minimum,
-				ExclusiveMin:     p.exclusiveMin,
-				ExclusiveMax:     p.exclusiveMax,
-				MultipleOf:       p.multipleOf,
-				XMLRepr:          buildSwagXMLRepr(p.xmlRepr),
-				Items:            items,
+				Type:                 typ,
+				Format:               format,
+				Description:          p.desc,
+				ReadOnly:             p.readOnly,
+				Nullable:             p.nullable,
+				AllowEmpty:           p.allowEmpty, // ?
+				Default:              p.defaul,
+				Example:              p.example,
+				Pattern:              p.pattern,
+				Enum:                 p.enum,
+				MaxLength:            p.maxLength,
+				MinLength:            p.minLength,
+				MaxItems:             p.maxItems,
+				MinItems:             p.minItems,
+				UniqueItems:          p.uniqueItems,
+				CollectionFormat:     p.collectionFormat, // ?
+				Maximum:              p.maximum,
+				Minimum:              p.minimum,
+				ExclusiveMin:         p.exclusiveMin,
+				ExclusiveMax:         p.exclusiveMax,
+				MultipleOf:           p.multipleOf,
+				XMLRepr:              buildSwagXMLRepr(p.xmlRepr),
+				Items:                items,
+				AdditionalProperties: additional,
 			}
 		}
-		properties.Set(p.name, schema)
+		out.Set(p.name, schema)
 	}
+	return required, out, nil
+}
 
-	return &swagDefinition{
-		Type:        OBJECT, // fixed schema type to object
-		Required:    required,
-		Description: definition.desc,
-		XMLRepr:     buildSwagXMLRepr(definition.xmlRepr),
-		Properties:  properties,
+func buildSwagDefinition(definition *Definition) (*swagDefinition, error) {
+	if definition.enumType != "" {
+		at, err := parseApiType(definition.enumType)
+		if err != nil {
+			return nil, err
+		}
+		return &swagDefinition{
+			Type:         at.prime.typ, // enum type has been checked
+			Format:       at.prime.format,
+			Description:  definition.desc,
+			XMLRepr:      buildSwagXMLRepr(definition.xmlRepr),
+			Enum:         definition.enums,
+			EnumVarNames: definition.enumVarNames,
+			EnumDescs:    definition.enumDescs,
+		}, nil
 	}
+
+	required, properties, err := buildSwagProperties(definition.properties)
+	if err != nil {
+		return nil, err
+	}
+	allOf := make([]*swagSchema, 0, len(definition.extends))
+	for _, ext := range definition.extends {
+		allOf = append(allOf, &swagSchema{OriginRef: ext, Ref: "#/definitions/" + ext})
+	}
+
+	return &swagDefinition{
+		Type:          OBJECT, // fixed schema type to object
+		Required:      required,
+		Description:   definition.desc,
+		Discriminator: definition.discriminator,
+		XMLRepr:       buildSwagXMLRepr(definition.xmlRepr),
+		AllOf:         allOf,
+		Properties:    properties,
+	}, nil
 }
 
 // ========================
 // operations & definitions
 // ========================
 
-func buildSwagOperations(doc *Document) map[string]map[string]*swagOperation {
+func buildSwagOperations(doc *Document) (map[string]map[string]*swagOperation, error) {
 	// route - method - operation
 	out := make(map[string]map[string]*swagOperation, 2) // cap defaults to 2
+	enums := collectEnumDefinitions(doc.definitions)
+	naming := genericNamingOf(doc)
 	for _, op := range doc.operations {
 		method := strings.ToLower(op.method)
-		operationId := op.operationId
-		if operationId == "" {
-			operationId = strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(
-				op.route, "/", "-"), "{", ":"), "}", "") + "-" + method
-		}
+		operationId := operationIdOf(op)
 		securities := make([]map[string][]string, 0, len(op.securities))
 		for _, s := range op.securities {
 			secReq := map[string][]string{s: {}}
@@ -531,18 +727,16 @@ func buildSwagOperations(doc *Document) map[string]map[string]*swagOperation {
 		}
 		params := op.params
 		if opt := doc.option; opt != nil {
-			for _, globalParam := range opt.globalParams {
-				existed := false
-				for _, existedParam := range params {
-					if existedParam.name == globalParam.name {
-						existed = true
-						break
-					}
-				}
-				if !existed {
-					params = append(params, globalParam)
-				}
-			}
+			params = operationParams(op, opt.globalParams)
+		}
+
+		swagParams, err := buildSwagParams(nameGenericParams(params, naming), enums)
+		if err != nil {
+			return nil, errorInOperation(err, op)
+		}
+		swagResponses, err := buildSwagResponses(nameGenericResponses(op.responses, naming))
+		if err != nil {
+			return nil, errorInOperation(err, op)
 		}
 
 		_, ok := out[op.route]
@@ -560,37 +754,45 @@ func buildSwagOperations(doc *Document) map[string]map[string]*swagOperation {
 			Securities:  securities,
 			Deprecated:  op.deprecated,
 			ExternalDoc: buildSwagExternalDoc(op.externalDoc),
-			Parameters:  buildSwagParams(params),
-			Responses:   buildSwagResponses(op.responses),
+			Parameters:  swagParams,
+			Responses:   swagResponses,
 		}
 	}
-	return out
+	return out, nil
 }
 
-func buildSwagDefinitions(doc *Document) map[string]*swagDefinition {
+func buildSwagDefinitions(doc *Document) (map[string]*swagDefinition, error) {
 	// prehandle definition list
-	allSpecTypes := collectAllSpecTypes(doc)
-	clonedDefinitions := make([]*Definition, 0, len(doc.definitions))
-	for _, definition := range doc.definitions {
-		clonedDefinitions = append(clonedDefinitions, prehandleDefinition(definition)) // with generic name checked
+	newDefinitionList, err := prehandleAllDefinitions(doc)
+	if err != nil {
+		return nil, err
 	}
-	newDefinitionList := prehandleDefinitionList(clonedDefinitions, allSpecTypes)
 
 	// return result map
 	out := make(map[string]*swagDefinition, len(newDefinitionList))
 	for _, definition := range newDefinitionList {
-		out[definition.name] = buildSwagDefinition(definition)
+		def, err := buildSwagDefinition(definition)
+		if err != nil {
+			return nil, errorInDefinition(err, definition.name)
+		}
+		out[definition.name] = def
 	}
-	return out
+	return out, nil
 }
 
 // ========
 // document
 // ========
 
-func buildSwagDocument(doc *Document) *swagDocument {
+func buildSwagDocument(doc *Document) (*swagDocument, error) {
 	// check
-	checkDocument(doc)
+	if err := checkDocument(doc); err != nil {
+		return nil, err
+	}
+	doc, err := fillDocumentExamples(doc, false)
+	if err != nil {
+		return nil, err
+	}
 
 	// info
 	out := &swagDocument{
@@ -641,8 +843,14 @@ func buildSwagDocument(doc *Document) *swagDocument {
 	}
 
 	// definitions & operations
-	out.Definitions = buildSwagDefinitions(doc)
-	out.Operations = buildSwagOperations(doc)
+	out.Definitions, err = buildSwagDefinitions(doc)
+	if err != nil {
+		return nil, err
+	}
+	out.Operations, err = buildSwagOperations(doc)
+	if err != nil {
+		return nil, err
+	}
 
-	return out
+	return out, nil
 }
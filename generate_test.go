@@ -3,6 +3,7 @@ package goapidoc
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -690,6 +691,16 @@ func TestGenerate3(t *testing.T) {
 				NewResponse(200, "_Result<UserDto>"),
 			),
 
+		NewGetOperation("/user/batch", "Query users by ids").
+			Tags("User").
+			Securities("jwt").
+			Params(
+				NewQueryParam("ids", "integer#int64[]", true, "user ids"),
+			).
+			Responses(
+				NewResponse(200, "_Result<map<string, UserDto>>").Desc("users keyed by id"),
+			),
+
 		NewPutOperation("/user", "Update the authorized user").
 			Tags("User").
 			Securities("jwt").
@@ -748,7 +759,7 @@ func TestGenerate3(t *testing.T) {
 			Properties(
 				NewProperty("username", "string", true, "username"),
 				NewProperty("bio", "string", true, "user bio"),
-				NewProperty("gender", "string", true, "user gender").Enum("Secret", "Male", "Female"),
+				NewProperty("gender", "Gender", true, "user gender"),
 				NewProperty("birthday", "string#date", true, "user birthday"),
 			),
 
@@ -763,21 +774,41 @@ func TestGenerate3(t *testing.T) {
 				NewProperty("id", "integer#int64", true, "user id"),
 				NewProperty("username", "string", true, "username"),
 				NewProperty("bio", "string", true, "user bio"),
-				NewProperty("gender", "string", true, "user gender").Enum("Secret", "Male", "Female"),
+				NewProperty("gender", "Gender", true, "user gender"),
 				NewProperty("birthday", "string#date", true, "user birthday"),
+				NewProperty("extra", "map<string, string>", false, "user extra information"),
 			),
+
+		NewEnumDefinition("Gender", "string", "Secret", "Male", "Female").Desc("User gender"),
 	)
 
 	_generate(t, "api3")
 }
 
 func _generate(t *testing.T, name string) {
+	for _, issue := range ValidateDocument() {
+		if issue.Severity == SEVERITY_ERROR {
+			failNow(t, fmt.Sprintf("ValidateDocument (%s) error: %s", name, issue))
+		}
+	}
 	if _, err := GenerateSwaggerYaml(); err != nil {
 		failNow(t, fmt.Sprintf("GenerateSwaggerYaml (%s) error: %v", name, err))
 	}
 	if _, err := GenerateSwaggerJson(); err != nil {
 		failNow(t, fmt.Sprintf("GenerateSwaggerJson (%s) error: %v", name, err))
 	}
+	if _, err := GenerateOpenAPI3Yaml(); err != nil {
+		failNow(t, fmt.Sprintf("GenerateOpenAPI3Yaml (%s) error: %v", name, err))
+	}
+	if _, err := GenerateOpenAPI3Json(); err != nil {
+		failNow(t, fmt.Sprintf("GenerateOpenAPI3Json (%s) error: %v", name, err))
+	}
+	if _, err := GenerateOpenAPI31Yaml(); err != nil {
+		failNow(t, fmt.Sprintf("GenerateOpenAPI31Yaml (%s) error: %v", name, err))
+	}
+	if _, err := GenerateOpenAPI31Json(); err != nil {
+		failNow(t, fmt.Sprintf("GenerateOpenAPI31Json (%s) error: %v", name, err))
+	}
 	EnableWarningLogger()
 	if _, err := GenerateApib(); err != nil {
 		failNow(t, fmt.Sprintf("GenerateApib (%s) error: %v", name, err))
@@ -789,6 +820,18 @@ func _generate(t *testing.T, name string) {
 	if _, err := SaveSwaggerJson("./docs/" + name + ".json"); err != nil {
 		failNow(t, fmt.Sprintf("SaveSwaggerJson (%s) error: %v", name, err))
 	}
+	if _, err := SaveOpenAPI3Yaml("./docs/" + name + ".oas3.yaml"); err != nil {
+		failNow(t, fmt.Sprintf("SaveOpenAPI3Yaml (%s) error: %v", name, err))
+	}
+	if _, err := SaveOpenAPI3Json("./docs/" + name + ".oas3.json"); err != nil {
+		failNow(t, fmt.Sprintf("SaveOpenAPI3Json (%s) error: %v", name, err))
+	}
+	if _, err := SaveOpenAPI31Yaml("./docs/" + name + ".oas31.yaml"); err != nil {
+		failNow(t, fmt.Sprintf("SaveOpenAPI31Yaml (%s) error: %v", name, err))
+	}
+	if _, err := SaveOpenAPI31Json("./docs/" + name + ".oas31.json"); err != nil {
+		failNow(t, fmt.Sprintf("SaveOpenAPI31Json (%s) error: %v", name, err))
+	}
 	if _, err := SaveApib("./docs/" + name + ".apib"); err != nil {
 		failNow(t, fmt.Sprintf("SaveApib (%s) error: %v", name, err))
 	}
@@ -881,8 +924,32 @@ func TestCheckDocument(t *testing.T) {
 			Params(NewParam("name", "path", "", true, "").AllowEmpty(true)))},
 		{"doc.operations.params.typ", demoDoc().Operations(NewOperation("get", "/", "summary").Responses(demoResp()).
 			Params(NewParam("name", "path", "", true, "")))},
-		{"success", demoDoc().Operations(NewOperation("get", "/", "summary").Responses(demoResp()).
+		{"doc.operations.params.route", demoDoc().Operations(NewOperation("get", "/", "summary").Responses(demoResp()).
+			Params(NewParam("name", "path", "string", true, "")))},
+		{"doc.operations.route.placeholder", demoDoc().Operations(NewOperation("get", "/{name}/{id}", "summary").Responses(demoResp()).
 			Params(NewParam("name", "path", "string", true, "")))},
+		{"doc.operations.route.placeholder.empty", demoDoc().Operations(NewOperation("get", "/{}", "summary").Responses(demoResp()))},
+		{"doc.operations.route.placeholder.duplicate", demoDoc().Operations(NewOperation("get", "/{name}/{name}", "summary").Responses(demoResp()).
+			Params(NewParam("name", "path", "string", true, "")))},
+		{"success", demoDoc().Operations(NewOperation("get", "/{name}", "summary").Responses(demoResp()).
+			Params(NewParam("name", "path", "string", true, "")))},
+		{"success", demoDoc().Option(NewOption().GlobalParams(NewPathParam("version", "string", true, ""))).
+			Operations(NewOperation("get", "/{version}/{name}", "summary").Responses(demoResp()).Params(NewPathParam("name", "string", true, "")))},
+		{"doc.operations.conflict", demoDoc().Operations(
+			NewOperation("get", "/user/{id}", "summary").Responses(demoResp()).Params(NewPathParam("id", "string", true, "")),
+			NewOperation("GET", "/user/{uid}", "summary").Responses(demoResp()).Params(NewPathParam("uid", "string", true, "")))},
+		{"doc.operations.securities", demoDoc().Operations(demoOp().Securities("jwt"))},
+		{"doc.operations.securityScopes", demoDoc().Operations(demoOp().SetSecurityScopes("jwt", "read"))},
+		{"doc.operations.securityScopes.scope", demoDoc().Option(NewOption().Securities(NewOAuth2Security("oauth", PASSWORD_FLOW).TokenUrl("token").
+			Scopes(NewSecurityScope("read", "")))).Operations(demoOp().Securities("oauth").SetSecurityScopes("oauth", "read", "write"))},
+		{"success", demoDoc().Option(NewOption().Securities(NewOAuth2Security("oauth", PASSWORD_FLOW).TokenUrl("token").
+			Scopes(NewSecurityScope("read", "")))).Operations(demoOp().Tags("undeclared").Securities("oauth").SetSecurityScopes("oauth", "read"))},
+		{"doc.operations.operationId", demoDoc().Operations(demoOp().OperationId("op"), NewOperation("post", "/", "summary").Responses(demoResp()).OperationId("op"))},
+		{"doc.operations.operationId.generated", demoDoc().Operations(demoOp(), NewOperation("post", "/", "summary").Responses(demoResp()).OperationId("--get"))},
+		{"success", demoDoc().Operations(
+			NewOperation("get", "/user/{id}", "summary").Responses(demoResp()).Params(NewPathParam("id", "string", true, "")),
+			NewOperation("put", "/user/{uid}", "summary").Responses(demoResp()).Params(NewPathParam("uid", "string", true, "")),
+			NewOperation("get", "/user/{id}/x", "summary").Responses(demoResp()).Params(NewPathParam("id", "string", true, "")))},
 
 		{"doc.definitions.name", demoDoc().Operations(demoOp().Responses(demoResp())).Definitions(NewDefinition("", ""))},
 		{"success", demoDoc().Operations(demoOp().Responses(demoResp())).Definitions(NewDefinition("name", ""))},
@@ -892,11 +959,21 @@ func TestCheckDocument(t *testing.T) {
 			Properties(NewProperty("name", "", true, "")))},
 		{"success", demoDoc().Operations(demoOp().Responses(demoResp())).Definitions(NewDefinition("name", "").
 			Properties(NewProperty("name", "integer", true, "")))},
+		{"doc.definitions.discriminator", demoDoc().Operations(demoOp()).Definitions(NewDefinition("Base", "").
+			Discriminator("", map[string]string{"a": "A"}))},
+		{"doc.definitions.discriminator.required", demoDoc().Operations(demoOp()).Definitions(NewDefinition("Base", "").
+			Discriminator("kind", nil).Properties(NewProperty("kind", "string", false, "")))},
+		{"doc.definitions.discriminator.mapping", demoDoc().Operations(demoOp()).Definitions(NewDefinition("Base", "").
+			Discriminator("kind", map[string]string{"a": "A"}).Properties(NewProperty("kind", "string", true, "")))},
+		{"doc.definitions.discriminator.subtype", demoDoc().Operations(demoOp()).Definitions(NewDefinition("Base", "").
+			Discriminator("kind", map[string]string{"a": "A"}).Properties(NewProperty("kind", "string", true, "")), NewDefinition("A", ""))},
+		{"success", demoDoc().Operations(demoOp()).Definitions(NewDefinition("Base", "").
+			Discriminator("kind", map[string]string{"a": "A", "b": "B"}).Properties(NewProperty("kind", "string", true, "")),
+			NewDefinition("A", "").Extends("Base"), NewDefinition("B", "").Properties(NewProperty("kind", "string", true, "")))},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			testPanic(t, tc.name != "success", func() {
-				_, _ = tc.giveDoc.GenerateSwaggerJson()
-			}, tc.name)
+			_, err := tc.giveDoc.GenerateSwaggerJson()
+			testError(t, tc.name != "success", err, tc.name)
 		})
 	}
 
@@ -973,9 +1050,8 @@ func TestGenerateError(t *testing.T) {
 			AddResponses(NewResponse(200, "").AddHeaders(NewResponseHeader("head", "string[]", ""))))},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			testPanic(t, tc.name != "success", func() {
-				_, _ = tc.give.GenerateSwaggerJson()
-			}, tc.name)
+			_, err := tc.give.GenerateSwaggerJson()
+			testError(t, tc.name != "success", err, tc.name)
 		})
 	}
 
@@ -1017,3 +1093,771 @@ func TestGenerateError(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentError(t *testing.T) {
+	newDoc := func() *Document { return NewDocument("host", "/", NewInfo("title", "", "1.0.0")) }
+	for _, tc := range []struct {
+		name     string
+		give     *Document
+		wantErr  *DocumentError
+		wantText string
+	}{
+		{"document", NewDocument("", "/", nil), &DocumentError{Message: "Host is required"},
+			"Host is required"},
+		{"param", newDoc().AddOperations(NewOperation("get", "/{id}", "s").AddResponses(NewResponse(200, "")).
+			Params(NewPathParam("id", "integer", false, ""))), &DocumentError{Method: "GET", Route: "/{id}", Field: "id", Message: "Path param's must be non-optional and non-empty"},
+			"operation `GET /{id}`: `id`: Path param's must be non-optional and non-empty"},
+		{"param type", newDoc().AddOperations(NewOperation("post", "/", "s").AddResponses(NewResponse(200, "")).
+			Params(NewQueryParam("q", "integer<T>", true, ""))), &DocumentError{Method: "POST", Route: "/", Field: "q", Message: "Invalid type `integer<T>`"},
+			"operation `POST /`: `q`: Invalid type `integer<T>`"},
+		{"response type", newDoc().AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "array"))), &DocumentError{Method: "GET", Route: "/", Field: "200", Message: "Use array as type invalidly"},
+			"operation `GET /`: `200`: Use array as type invalidly"},
+		{"property type", newDoc().AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, ""))).
+			AddDefinitions(NewDefinition("Obj", "").AddProperties(NewProperty("p", "$", true, ""))), &DocumentError{Definition: "Obj", Field: "p", Message: "Invalid type `$`"},
+			"definition `Obj`: `p`: Invalid type `$`"},
+		{"generic type", newDoc().AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, ""))).
+			AddDefinitions(NewDefinition("Obj", "").Generics("T-T")), &DocumentError{Definition: "Obj", Message: "Invalid generic type `T-T`"},
+			"definition `Obj`: Invalid generic type `T-T`"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, fn := range []func() ([]byte, error){tc.give.GenerateSwaggerJson, tc.give.GenerateOpenAPI3Json, tc.give.GenerateOpenAPI31Json, tc.give.GenerateApib} {
+				_, err := fn()
+				e, ok := err.(*DocumentError)
+				if !ok {
+					failNow(t, fmt.Sprintf("Generate should return DocumentError but got %v", err))
+				}
+				if *e != *tc.wantErr || e.Error() != tc.wantText {
+					failNow(t, fmt.Sprintf("Generate returns an unexpected DocumentError: %s", e.Error()))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPI31(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Obj"))).
+		AddDefinitions(NewDefinition("Obj", "").AddProperties(
+			NewProperty("a", "integer", true, "").Minimum(1).ExclusiveMin(true).Example(2),
+			NewProperty("b", "number", true, "").Maximum(5).ExclusiveMax(true),
+			NewProperty("c", "integer", true, "").Minimum(0).Maximum(1),
+		))
+
+	out, _ := buildOas31Document(doc)
+	if out.OpenAPI != "3.1.0" {
+		failNow(t, "buildOas31Document get a wrong openapi version")
+	}
+	props := out.Components.Schemas["Obj"].Properties
+	a := props.MustGet("a").(*oas3Schema)
+	if a.ExclusiveMin != 1.0 || a.Minimum != nil || a.Example != nil || len(a.Examples) != 1 || a.Examples[0] != 2 {
+		failNow(t, "buildOas31Document get a wrong schema for property a")
+	}
+	b := props.MustGet("b").(*oas3Schema)
+	if b.ExclusiveMax != 5.0 || b.Maximum != nil {
+		failNow(t, "buildOas31Document get a wrong schema for property b")
+	}
+	c := props.MustGet("c").(*oas3Schema)
+	if c.ExclusiveMin != nil || c.ExclusiveMax != nil || *c.Minimum != 0 || *c.Maximum != 1 {
+		failNow(t, "buildOas31Document get a wrong schema for property c")
+	}
+}
+
+func TestGenerateMapType(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("post", "/", "s").
+			AddParams(NewBodyParam("body", "map<string, integer>", true, "")).
+			AddResponses(NewResponse(200, "map<string, Obj>"))).
+		AddDefinitions(NewDefinition("Obj", "").AddProperties(
+			NewProperty("a", "map<string, map<string, string[]>>", true, ""),
+			NewProperty("b", "map<string, boolean>[]", true, ""),
+		))
+
+	swag, _ := buildSwagDocument(doc)
+	resp := swag.Operations["/"]["post"].Responses["200"].Schema
+	if resp.Type != OBJECT || resp.AdditionalProperties.Ref != "#/definitions/Obj" {
+		failNow(t, "buildSwagDocument get a wrong response schema")
+	}
+	a := swag.Definitions["Obj"].Properties.MustGet("a").(*swagSchema)
+	if a.Type != OBJECT || a.AdditionalProperties.Type != OBJECT || a.AdditionalProperties.AdditionalProperties.Items.Type != STRING {
+		failNow(t, "buildSwagDocument get a wrong schema for property a")
+	}
+	b := swag.Definitions["Obj"].Properties.MustGet("b").(*swagSchema)
+	if b.Type != ARRAY || b.Items.Type != OBJECT || b.Items.AdditionalProperties.Type != BOOLEAN {
+		failNow(t, "buildSwagDocument get a wrong schema for property b")
+	}
+
+	oas3, _ := buildOas3Document(doc)
+	body := oas3.Operations["/"]["post"].RequestBody.Content[JSON].Schema
+	if body.Type != OBJECT || body.AdditionalProperties.Type != INTEGER {
+		failNow(t, "buildOas3Document get a wrong request body schema")
+	}
+
+	_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(NewOperation("get", "/", "s").
+		AddParams(NewQueryParam("q", "map<string, string>", true, "")).AddResponses(NewResponse(200, ""))).GenerateSwaggerJson()
+	testError(t, true, err, "GenerateSwaggerJson")
+
+	// parse generated documents back
+	bs, _ := doc.GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	if parsed.operations[0].params[0].typ != "map<string, integer>" || parsed.operations[0].responses[0].typ != "map<string, Obj>" ||
+		parsed.definitions[0].properties[0].typ != "map<string, map<string, string[]>>" || parsed.definitions[0].properties[1].typ != "map<string, boolean>[]" {
+		failNow(t, "ParseSwaggerJson get wrong map types")
+	}
+	bs, _ = doc.GenerateApib()
+	parsed, err = ParseApib(bs)
+	testError(t, false, err, "ParseApib")
+	if parsed.operations[0].params[0].typ != "map<string, number>" || parsed.operations[0].responses[0].typ != "map<string, Obj>" ||
+		parsed.definitions[0].properties[0].typ != "map<string, map<string, string[]>>" {
+		failNow(t, "ParseApib get wrong map types")
+	}
+}
+
+func TestGenerateCompositionType(t *testing.T) {
+	DisableWarningLogger()
+	defer EnableWarningLogger()
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("post", "/", "s").
+			AddParams(NewBodyParam("body", "anyOf<Cat, Dog>", true, "")).
+			AddResponses(NewResponse(200, "Result<Cat|Dog>"))).
+		AddDefinitions(
+			NewDefinition("Base", "").AddProperties(NewProperty("id", "integer", true, "")),
+			NewDefinition("Cat", "").Extends("Base").AddProperties(NewProperty("pet", "Cat|Dog|string", true, "")),
+			NewDefinition("Dog", "").Extends("Base").AddProperties(NewProperty("bark", "boolean", true, "")),
+			NewDefinition("Result", "").Generics("T").Extends("Base").AddProperties(NewProperty("data", "T", true, "")),
+		)
+
+	swag, _ := buildSwagDocument(doc)
+	if cat := swag.Definitions["Cat"]; len(cat.AllOf) != 1 || cat.AllOf[0].Ref != "#/definitions/Base" {
+		failNow(t, "buildSwagDocument get a wrong allOf for definition Cat")
+	}
+	if res, ok := swag.Definitions["Result<oneOf<Cat, Dog>>"]; !ok || res.AllOf[0].Ref != "#/definitions/Base" ||
+		res.Properties.MustGet("data").(*swagSchema).Type != OBJECT {
+		failNow(t, "buildSwagDocument get a wrong definition Result<oneOf<Cat, Dog>>")
+	}
+	if ref := swag.Operations["/"]["post"].Responses["200"].Schema.Ref; ref != "#/definitions/Result<oneOf<Cat, Dog>>" {
+		failNow(t, "buildSwagDocument get a wrong $ref to definition Result<oneOf<Cat, Dog>>: "+ref)
+	}
+
+	oas3, _ := buildOas3Document(doc)
+	body := oas3.Operations["/"]["post"].RequestBody.Content[JSON].Schema
+	if len(body.AnyOf) != 2 || body.AnyOf[0].Ref != "#/components/schemas/Cat" || body.AnyOf[1].Ref != "#/components/schemas/Dog" {
+		failNow(t, "buildOas3Document get a wrong request body schema")
+	}
+	pet := oas3.Components.Schemas["Cat"].Properties.MustGet("pet").(*oas3Schema)
+	if len(pet.OneOf) != 3 || pet.OneOf[1].Ref != "#/components/schemas/Dog" || pet.OneOf[2].Type != STRING {
+		failNow(t, "buildOas3Document get a wrong schema for property pet")
+	}
+	if dog := oas3.Components.Schemas["Dog"]; len(dog.AllOf) != 1 || dog.AllOf[0].Ref != "#/components/schemas/Base" {
+		failNow(t, "buildOas3Document get a wrong allOf for definition Dog")
+	}
+
+	// union generic arguments are kept as unions when used in array and map types
+	unionDoc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").
+			AddResponses(NewResponse(200, "Page<Cat|Dog>"), NewResponse(201, "Page<oneOf<Cat, Dog>>"), NewResponse(202, "Page<Cat | Dog>"))).
+		AddDefinitions(
+			NewDefinition("Cat", "").AddProperties(NewProperty("meow", "boolean", true, "")),
+			NewDefinition("Dog", "").AddProperties(NewProperty("bark", "boolean", true, "")),
+			NewDefinition("Page", "").Generics("T").AddProperties(NewProperty("data", "T[]", true, ""), NewProperty("index", "map<string, T>", true, "")),
+		)
+	definitions, err := prehandleAllDefinitions(unionDoc)
+	testError(t, false, err, "prehandleAllDefinitions")
+	pageCount := 0
+	for _, def := range definitions {
+		if strings.HasPrefix(def.name, "Page<") {
+			pageCount++
+			if def.name != "Page<oneOf<Cat, Dog>>" || def.properties[0].typ != "oneOf<Cat, Dog>[]" || def.properties[1].typ != "map<string, oneOf<Cat, Dog>>" {
+				failNow(t, "prehandleAllDefinitions get wrong definition for union generic argument: "+def.name+", "+def.properties[0].typ)
+			}
+		}
+	}
+	if pageCount != 1 {
+		failNow(t, "prehandleAllDefinitions get duplicate definitions for the same union generic argument")
+	}
+	oas3, _ = buildOas3Document(unionDoc)
+	for _, code := range []string{"200", "201", "202"} {
+		if ref := oas3.Operations["/"]["get"].Responses[code].Content[JSON].Schema.Ref; ref != "#/components/schemas/Page<oneOf<Cat, Dog>>" {
+			failNow(t, "buildOas3Document get a wrong $ref for union generic argument: "+ref)
+		}
+	}
+	page := oas3.Components.Schemas["Page<oneOf<Cat, Dog>>"]
+	if data := page.Properties.MustGet("data").(*oas3Schema); data.Type != ARRAY || data.Items == nil || len(data.Items.OneOf) != 2 {
+		failNow(t, "buildOas3Document get a wrong schema for array of union generic argument")
+	}
+	if index := page.Properties.MustGet("index").(*oas3Schema); index.AdditionalProperties == nil || len(index.AdditionalProperties.OneOf) != 2 {
+		failNow(t, "buildOas3Document get a wrong schema for map of union generic argument")
+	}
+	swag, _ = buildSwagDocument(unionDoc)
+	if data := swag.Definitions["Page<oneOf<Cat, Dog>>"].Properties.MustGet("data").(*swagSchema); data.Type != ARRAY || data.Items == nil {
+		failNow(t, "buildSwagDocument get a wrong schema for array of union generic argument")
+	}
+
+	for _, ext := range []string{"string", "Base[]", "Unknown", "Cat|Dog"} {
+		_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+			AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Sub"))).
+			AddDefinitions(NewDefinition("Base", ""), NewDefinition("Cat", ""), NewDefinition("Dog", ""), NewDefinition("Sub", "").Extends(ext)).
+			GenerateSwaggerJson()
+		testError(t, true, err, "GenerateSwaggerJson")
+	}
+
+	// parse generated documents back, notes that union types are generated as object in swagger
+	bs, _ := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Cat"))).
+		AddDefinitions(NewDefinition("Base", ""), NewDefinition("Cat", "").Extends("Base")).GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	for _, def := range parsed.definitions {
+		if def.name == "Cat" && (len(def.extends) != 1 || def.extends[0] != "Base") {
+			failNow(t, "ParseSwaggerJson get wrong extends")
+		}
+	}
+	bs, _ = doc.GenerateApib()
+	parsed, err = ParseApib(bs)
+	testError(t, false, err, "ParseApib")
+	if parsed.operations[0].params[0].typ != "Cat|Dog" {
+		failNow(t, "ParseApib get a wrong union type for request body")
+	}
+	for _, def := range parsed.definitions {
+		if def.name == "Cat" && (len(def.extends) != 1 || def.extends[0] != "Base" || def.properties[0].typ != "Cat|Dog|string") {
+			failNow(t, "ParseApib get wrong extends or union type")
+		}
+	}
+}
+
+func TestGenerateDiscriminator(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Notification[]"))).
+		AddDefinitions(
+			NewDefinition("Notification", "").Discriminator("kind", map[string]string{"email": "EmailNotification", "sms": "SmsNotification"}).
+				AddProperties(NewProperty("kind", "string", true, "")),
+			NewDefinition("EmailNotification", "").Extends("Notification").AddProperties(NewProperty("email", "string", true, "")),
+			NewDefinition("SmsNotification", "").Extends("Notification").AddProperties(NewProperty("phone", "string", true, "")),
+		)
+
+	swag, _ := buildSwagDocument(doc)
+	if d := swag.Definitions["Notification"]; d.Discriminator != "kind" || len(d.Required) != 1 || d.Required[0] != "kind" {
+		failNow(t, "buildSwagDocument get a wrong discriminator")
+	}
+	oas3, _ := buildOas3Document(doc)
+	if d := oas3.Components.Schemas["Notification"].Discriminator; d == nil || d.PropertyName != "kind" || len(d.Mapping) != 2 ||
+		d.Mapping["email"] != "#/components/schemas/EmailNotification" || d.Mapping["sms"] != "#/components/schemas/SmsNotification" {
+		failNow(t, "buildOas3Document get a wrong discriminator")
+	}
+	if oas3.Components.Schemas["EmailNotification"].Discriminator != nil {
+		failNow(t, "buildOas3Document get an unexpected discriminator")
+	}
+	if issues := doc.Validate(); len(issues) != 0 {
+		failNow(t, fmt.Sprintf("Validate should return no issue but got %v", issues))
+	}
+
+	issues := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Notification"))).
+		AddDefinitions(
+			NewDefinition("Notification", "").Discriminator("kind", map[string]string{"email": "EmailNotification", "push": "Push"}).
+				AddProperties(NewProperty("kind", "string", true, "")),
+			NewDefinition("EmailNotification", "").AddProperties(NewProperty("kind", "string", false, "")),
+		).Validate()
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] definitions[EmailNotification]: Subtype of `Notification` must have the required discriminator property `kind`",
+		"[error] definitions[Notification].discriminator.mapping[push]: Discriminator subtype `Push` not found",
+	}, "Validate", "expected issues")
+
+	// parse generated document back
+	bs, _ := doc.GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	for _, def := range parsed.definitions {
+		if def.name == "Notification" && def.discriminator != "kind" {
+			failNow(t, "ParseSwaggerJson get a wrong discriminator")
+		}
+	}
+}
+
+func TestGenerateInlineObject(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("post", "/", "s").
+			AddParams(NewBodyParam("body", "object[]", true, "").InlineObject(NewInlineObject(
+				NewProperty("name", "string", true, ""),
+			))).
+			AddResponses(NewResponse(200, "object").InlineObject(NewInlineObject(
+				NewProperty("count", "integer", true, ""),
+				NewProperty("user", "object", false, "").InlineObject(NewInlineObject(NewProperty("id", "integer#int64", true, ""))),
+				NewProperty("owner", "User", false, ""),
+			)))).
+		AddDefinitions(NewDefinition("User", "").AddProperties(
+			NewProperty("tags", "object[]", true, "").InlineObject(NewInlineObject(NewProperty("tag", "string", false, ""))),
+		))
+
+	swag, _ := buildSwagDocument(doc)
+	op := swag.Operations["/"]["post"]
+	if body := op.Parameters[0].Schema; body.Type != ARRAY || body.Items.Type != OBJECT || body.Items.Required[0] != "name" ||
+		body.Items.Properties.MustGet("name").(*swagSchema).Type != STRING {
+		failNow(t, "buildSwagDocument get a wrong inline body param")
+	}
+	if resp := op.Responses["200"].Schema; resp.Type != OBJECT || resp.Properties.Length() != 3 ||
+		resp.Properties.MustGet("user").(*swagSchema).Properties.MustGet("id").(*swagSchema).Format != INT64 {
+		failNow(t, "buildSwagDocument get a wrong inline response")
+	}
+	if tags := swag.Definitions["User"].Properties.MustGet("tags").(*swagSchema); tags.Type != ARRAY || tags.Items.Properties.Length() != 1 {
+		failNow(t, "buildSwagDocument get a wrong inline property")
+	}
+
+	oas3, _ := buildOas3Document(doc)
+	if body := oas3.Operations["/"]["post"].RequestBody.Content[JSON].Schema; body.Type != ARRAY || body.Items.Type != OBJECT ||
+		body.Items.Properties.MustGet("name").(*oas3Schema).Type != STRING {
+		failNow(t, "buildOas3Document get a wrong inline request body")
+	}
+	if resp := oas3.Operations["/"]["post"].Responses["200"].Content[JSON].Schema; resp.Type != OBJECT || resp.Required[0] != "count" {
+		failNow(t, "buildOas3Document get a wrong inline response")
+	}
+	if issues := doc.Validate(); len(issues) != 0 {
+		failNow(t, fmt.Sprintf("Validate should return no issue but got %v", issues))
+	}
+
+	for _, tc := range []struct {
+		giveParam *Param
+		giveProp  *Property
+	}{
+		{NewQueryParam("q", "object", true, "").InlineObject(NewInlineObject()), nil},
+		{NewBodyParam("body", "string", true, "").InlineObject(NewInlineObject()), nil},
+		{nil, NewProperty("p", "User", true, "").InlineObject(NewInlineObject())},
+		{nil, NewProperty("p", "object", true, "").InlineObject(NewInlineObject(NewProperty("", "string", true, "")))},
+		{nil, NewProperty("p", "object", true, "").InlineObject(NewInlineObject(NewProperty("x", "Unknown", true, "")))},
+	} {
+		op := NewOperation("get", "/", "s").AddResponses(NewResponse(200, "User"))
+		if tc.giveParam != nil {
+			op.AddParams(tc.giveParam)
+		}
+		def := NewDefinition("User", "")
+		if tc.giveProp != nil {
+			def.AddProperties(tc.giveProp)
+		}
+		_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(op).AddDefinitions(def).GenerateSwaggerJson()
+		testError(t, true, err, "GenerateSwaggerJson")
+	}
+
+	// parse generated documents back
+	bs, _ := doc.GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	testInlineObject := func(parsed *Document, fn string) {
+		op := parsed.operations[0]
+		if p := op.params[0]; p.typ != "object[]" || p.inlineObject == nil || p.inlineObject.properties[0].name != "name" {
+			failNow(t, fn+" get a wrong inline body param")
+		}
+		if r := op.responses[0]; r.typ != OBJECT || r.inlineObject == nil || len(r.inlineObject.properties) != 3 ||
+			r.inlineObject.properties[1].inlineObject.properties[0].typ != "integer#int64" {
+			failNow(t, fn+" get a wrong inline response")
+		}
+		if p := parsed.definitions[0].properties[0]; p.typ != "object[]" || p.inlineObject == nil || p.inlineObject.properties[0].name != "tag" {
+			failNow(t, fn+" get a wrong inline property")
+		}
+	}
+	testInlineObject(parsed, "ParseSwaggerJson")
+	bs, _ = doc.GenerateApib()
+	parsed, err = ParseApib(bs)
+	testError(t, false, err, "ParseApib")
+	testInlineObject(parsed, "ParseApib")
+}
+
+func TestGenerateEnumDefinition(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").
+			AddParams(NewQueryParam("gender", "Gender", true, ""), NewQueryParam("levels", "Level[]", false, "")).
+			AddResponses(NewResponse(200, "User"))).
+		AddDefinitions(
+			NewEnumDefinition("Gender", "string", "Secret", "Male", "Female").Desc("user gender").
+				EnumVarNames("GenderSecret", "GenderMale", "GenderFemale").EnumDescs("secret", "male", "female"),
+			NewEnumDefinition("Level", "integer#int32", 1, 2, 3),
+			NewDefinition("User", "").AddProperties(NewProperty("gender", "Gender", true, ""), NewProperty("level", "Level", true, "")),
+		)
+
+	swag, _ := buildSwagDocument(doc)
+	if g := swag.Definitions["Gender"]; g.Type != STRING || len(g.Enum) != 3 || g.Enum[1] != "Male" || g.EnumVarNames[0] != "GenderSecret" ||
+		g.EnumDescs[2] != "female" || g.Description != "user gender" || g.Properties != nil {
+		failNow(t, "buildSwagDocument get a wrong enum definition Gender")
+	}
+	if l := swag.Definitions["Level"]; l.Type != INTEGER || l.Format != INT32 || len(l.Enum) != 3 || l.EnumVarNames != nil {
+		failNow(t, "buildSwagDocument get a wrong enum definition Level")
+	}
+	params := swag.Operations["/"]["get"].Parameters
+	if params[0].Type != STRING || len(params[0].Enum) != 3 || params[1].Type != ARRAY || params[1].Items.Type != INTEGER || len(params[1].Items.Enum) != 3 {
+		failNow(t, "buildSwagDocument get wrong enum query params")
+	}
+	if p := swag.Definitions["User"].Properties.MustGet("gender").(*swagSchema); p.Ref != "#/definitions/Gender" {
+		failNow(t, "buildSwagDocument get a wrong enum property")
+	}
+	oas3, _ := buildOas3Document(doc)
+	if g := oas3.Components.Schemas["Gender"]; g.Type != STRING || len(g.Enum) != 3 || g.EnumVarNames[1] != "GenderMale" || g.EnumDescs[0] != "secret" {
+		failNow(t, "buildOas3Document get a wrong enum definition Gender")
+	}
+	if p := oas3.Operations["/"]["get"].Parameters[0].Schema; p.Ref != "#/components/schemas/Gender" {
+		failNow(t, "buildOas3Document get a wrong enum query param")
+	}
+	if issues := doc.Validate(); len(issues) != 0 {
+		failNow(t, fmt.Sprintf("Validate should return no issue but got %v", issues))
+	}
+
+	issues := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "User"))).
+		AddDefinitions(
+			NewEnumDefinition("Gender", "string").EnumVarNames("A"),
+			NewEnumDefinition("Level", "Gender", 1).EnumDescs("a", "b").AddProperties(NewProperty("x", "string", true, "")),
+			NewDefinition("User", "").Extends("Gender").AddProperties(NewProperty("level", "Level", true, "")),
+		).Validate()
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] definitions[Gender].enum: Empty enum values is not allowed",
+		"[error] definitions[Gender].enumVarNames: Enum variable names length is not matched with enum values",
+		"[error] definitions[Level].enumType: Enum type `Gender` must be a primitive type",
+		"[error] definitions[Level].enumDescs: Enum descriptions length is not matched with enum values",
+		"[error] definitions[Level]: Enum definition cannot have properties, generics, extends or discriminator",
+		"[error] definitions[User].extends[Gender]: Extended type `Gender` must be an object type",
+	}, "Validate", "expected issues")
+
+	// parse generated documents back
+	bs, _ := doc.GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	for _, def := range parsed.definitions {
+		if def.name == "Gender" && (def.enumType != STRING || len(def.enums) != 3 || def.enumVarNames[2] != "GenderFemale" || def.enumDescs[1] != "male") {
+			failNow(t, "ParseSwaggerJson get a wrong enum definition Gender")
+		}
+		if def.name == "Level" && (def.enumType != INTEGER || len(def.enums) != 3) {
+			failNow(t, "ParseSwaggerJson get a wrong enum definition Level")
+		}
+	}
+	bs, _ = doc.GenerateApib()
+	parsed, err = ParseApib(bs)
+	testError(t, false, err, "ParseApib")
+	for _, def := range parsed.definitions {
+		if def.name == "Gender" && (def.enumType != STRING || len(def.enums) != 3 || def.enums[0] != "Secret" || def.enumDescs[2] != "female") {
+			failNow(t, "ParseApib get a wrong enum definition Gender")
+		}
+		if def.name == "Level" && (def.enumType != NUMBER || len(def.enums) != 3 || def.enumDescs != nil) {
+			failNow(t, "ParseApib get a wrong enum definition Level")
+		}
+		if def.name == "User" && def.properties[0].typ != "Gender" {
+			failNow(t, "ParseApib get a wrong enum property")
+		}
+	}
+}
+
+func TestGeneratePropertyModifiers(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("post", "/", "s").
+			AddParams(NewBodyParam("body", "User", true, "")).
+			AddResponses(NewResponse(200, "User"))).
+		AddDefinitions(
+			NewDefinition("User", "").AddProperties(
+				NewProperty("id", "integer#int64", true, "").ReadOnly(true),
+				NewProperty("password", "string", true, "").WriteOnly(true),
+				NewProperty("birthday", "string#date?", false, ""),
+				NewProperty("parent", "User?", false, ""),
+			),
+		)
+
+	swag, _ := buildSwagDocument(doc)
+	user := swag.Definitions["User"]
+	if len(user.Required) != 1 || user.Required[0] != "password" {
+		failNow(t, "buildSwagDocument get a wrong required list")
+	}
+	if id := user.Properties.MustGet("id").(*swagSchema); !id.ReadOnly || id.Nullable {
+		failNow(t, "buildSwagDocument get a wrong readOnly property")
+	}
+	if b := user.Properties.MustGet("birthday").(*swagSchema); b.Type != STRING || b.Format != DATE || !b.Nullable {
+		failNow(t, "buildSwagDocument get a wrong nullable property")
+	}
+	if p := user.Properties.MustGet("parent").(*swagSchema); p.Ref != "#/definitions/User" || !p.Nullable {
+		failNow(t, "buildSwagDocument get a wrong nullable $ref property")
+	}
+
+	oas3, _ := buildOas3Document(doc)
+	props := oas3.Components.Schemas["User"].Properties
+	if id, pwd := props.MustGet("id").(*oas3Schema), props.MustGet("password").(*oas3Schema); !id.ReadOnly || !pwd.WriteOnly {
+		failNow(t, "buildOas3Document get wrong readOnly or writeOnly properties")
+	}
+	if b := props.MustGet("birthday").(*oas3Schema); !b.Nullable {
+		failNow(t, "buildOas3Document get a wrong nullable property")
+	}
+	if p := props.MustGet("parent").(*oas3Schema); !p.Nullable || len(p.AllOf) != 1 || p.AllOf[0].Ref != "#/components/schemas/User" {
+		failNow(t, "buildOas3Document get a wrong nullable $ref property")
+	}
+	oas31, _ := buildOas31Document(doc)
+	props = oas31.Components.Schemas["User"].Properties
+	if b := props.MustGet("birthday").(*oas3Schema); b.Nullable || fmt.Sprint(b.Type) != "[string null]" {
+		failNow(t, "buildOas31Document get a wrong nullable property")
+	}
+	if p := props.MustGet("parent").(*oas3Schema); p.AllOf != nil || len(p.AnyOf) != 2 || p.AnyOf[1].Type != "null" {
+		failNow(t, "buildOas31Document get a wrong nullable $ref property")
+	}
+
+	_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "User"))).
+		AddDefinitions(NewDefinition("User", "").AddProperties(NewProperty("id", "integer", true, "").ReadOnly(true).WriteOnly(true))).
+		GenerateSwaggerJson()
+	testError(t, true, err, "GenerateSwaggerJson")
+
+	// parse generated documents back
+	bs, _ := doc.GenerateSwaggerJson()
+	parsed, err := ParseSwaggerJson(bs)
+	testError(t, false, err, "ParseSwaggerJson")
+	if p := parsed.definitions[0].properties; !p[0].readOnly || p[0].required || !p[2].nullable || p[2].typ != "string#date" || !p[3].nullable {
+		failNow(t, "ParseSwaggerJson get wrong property modifiers")
+	}
+	bs, _ = doc.GenerateApib()
+	if !strings.Contains(string(bs), "+ birthday (string, optional, nullable)") {
+		failNow(t, "GenerateApib get a wrong nullable property")
+	}
+	parsed, err = ParseApib(bs)
+	testError(t, false, err, "ParseApib")
+	if p := parsed.definitions[0].properties; p[0].nullable || !p[2].nullable || !p[3].nullable || p[3].typ != "User" {
+		failNow(t, "ParseApib get wrong property modifiers")
+	}
+}
+
+func TestGenerateGenericDefaults(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").
+			AddParams(NewBodyParam("body", "object", true, "")).
+			AddResponses(NewResponse(200, "_Result"), NewResponse(201, "_Page<UserDto>"), NewResponse(202, "_Page<UserDto, string>"))).
+		AddDefinitions(
+			NewDefinition("_Result", "").Generics("T").AddProperties(NewProperty("data", "T", true, "")),
+			NewDefinition("_Page", "").Generics("T: object", "U: primitive = integer#int64").AddProperties(
+				NewProperty("cursor", "U", true, ""),
+				NewProperty("data", "T[]", true, ""),
+			),
+			NewDefinition("UserDto", "").AddProperties(NewProperty("id", "integer", true, "")),
+		)
+	if issues := doc.Validate(); len(issues) != 0 {
+		failNow(t, "Validate should return no issue, but got "+issues[0].String())
+	}
+
+	swag, err := buildSwagDocument(doc)
+	testError(t, false, err, "buildSwagDocument")
+	if s := swag.Operations["/"]["get"].Responses["200"].Schema; s.Ref != "#/definitions/_Result" {
+		failNow(t, "buildSwagDocument get a wrong $ref to generic definition without arguments")
+	}
+	if d := swag.Definitions["_Result"]; d == nil || d.Properties.MustGet("data").(*swagSchema).Type != OBJECT {
+		failNow(t, "buildSwagDocument get a wrong generic definition without arguments")
+	}
+	if d := swag.Definitions["_Page<UserDto>"]; d == nil || d.Properties.MustGet("cursor").(*swagSchema).Format != INT64 {
+		failNow(t, "buildSwagDocument get a wrong generic definition with default argument")
+	}
+	if d := swag.Definitions["_Page<UserDto, string>"]; d == nil || d.Properties.MustGet("cursor").(*swagSchema).Type != STRING {
+		failNow(t, "buildSwagDocument get a wrong generic definition with given argument")
+	}
+	oas3, err := buildOas3Document(doc)
+	testError(t, false, err, "buildOas3Document")
+	if s := oas3.Components.Schemas["_Result"]; s == nil || s.Properties.MustGet("data").(*oas3Schema).Type != OBJECT {
+		failNow(t, "buildOas3Document get a wrong generic definition without arguments")
+	}
+	bs, err := doc.GenerateApib()
+	testError(t, false, err, "GenerateApib")
+	if !strings.Contains(string(bs), "## _Result (object)") || !strings.Contains(string(bs), "+ data (object, required)") {
+		failNow(t, "GenerateApib get a wrong generic definition without arguments")
+	}
+
+	for _, tc := range []struct {
+		name string
+		give string
+	}{
+		{"constraint not satisfied", "_Page<integer>"},
+		{"too many arguments", "_Page<UserDto, string, string>"},
+		{"free-form object in union", "UserDto|object"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+				AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, tc.give))).
+				AddDefinitions(doc.definitions...).
+				GenerateSwaggerJson()
+			testError(t, true, err, "GenerateSwaggerJson")
+		})
+	}
+	_, err = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddParams(NewQueryParam("q", "object", true, "")).AddResponses(NewResponse(200, ""))).
+		GenerateOpenAPI3Json()
+	testError(t, true, err, "GenerateOpenAPI3Json")
+}
+
+func TestGenerateGlobalPathParams(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		Option(NewOption().GlobalParams(NewPathParam("version", "string", true, ""), NewHeaderParam("X-Token", "string", false, ""))).
+		AddOperations(
+			NewOperation("get", "/{version}/user", "s").AddResponses(NewResponse(200, "")),
+			NewOperation("get", "/ping", "s").AddResponses(NewResponse(200, "")),
+		)
+	if issues := doc.Validate(); len(issues) != 0 {
+		failNow(t, fmt.Sprintf("Validate get unexpected issues: %v", issues))
+	}
+	swag, err := buildSwagDocument(doc)
+	testError(t, false, err, "buildSwagDocument")
+	if len(swag.Operations["/{version}/user"]["get"].Parameters) != 2 || len(swag.Operations["/ping"]["get"].Parameters) != 1 {
+		failNow(t, "buildSwagDocument does not skip the global path param for the route without matching placeholder")
+	}
+	oas3, err := buildOas3Document(doc)
+	testError(t, false, err, "buildOas3Document")
+	if len(oas3.Operations["/{version}/user"]["get"].Parameters) != 2 || len(oas3.Operations["/ping"]["get"].Parameters) != 1 {
+		failNow(t, "buildOas3Document does not skip the global path param for the route without matching placeholder")
+	}
+}
+
+func TestGenerateGenericNaming(t *testing.T) {
+	newDoc := func(naming GenericNamingFunc) *Document {
+		return NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+			Option(NewOption().GenericNaming(naming)).
+			AddOperations(NewOperation("post", "/", "s").
+				AddParams(NewBodyParam("body", "_Page<UserDto>", true, "")).
+				AddResponses(NewResponse(200, "_Result<_Page<UserDto>>"))).
+			AddDefinitions(
+				NewDefinition("_Result", "").Generics("T").AddProperties(NewProperty("data", "T", true, "")),
+				NewDefinition("_Page", "").Generics("T").AddProperties(NewProperty("data", "T[]", true, "")),
+				NewDefinition("UserDto", "").AddProperties(NewProperty("id", "integer", true, "")),
+			)
+	}
+	custom := func(base string, args []string) string {
+		return strings.ToUpper(base) + "__" + strings.Join(args, "__")
+	}
+	for _, tc := range []struct {
+		name       string
+		giveNaming GenericNamingFunc
+		wantResult string
+		wantPage   string
+	}{
+		{"default", nil, "_Result<_Page<UserDto>>", "_Page<UserDto>"},
+		{"of", OfGenericNaming, "ResultOfPageOfUserDto", "PageOfUserDto"},
+		{"underscore", UnderscoreGenericNaming, "_Result_Page_UserDto_", "_Page_UserDto_"},
+		{"custom", custom, "_RESULT___PAGE__UserDto", "_PAGE__UserDto"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := newDoc(tc.giveNaming)
+			swag, err := buildSwagDocument(doc)
+			testError(t, false, err, "buildSwagDocument")
+			op := swag.Operations["/"]["post"]
+			if op.Parameters[0].Schema.Ref != "#/definitions/"+tc.wantPage || op.Responses["200"].Schema.Ref != "#/definitions/"+tc.wantResult {
+				failNow(t, "buildSwagDocument get wrong named $ref in operation")
+			}
+			if d := swag.Definitions[tc.wantResult]; d == nil || d.Properties.MustGet("data").(*swagSchema).Ref != "#/definitions/"+tc.wantPage {
+				failNow(t, "buildSwagDocument get a wrong named definition")
+			}
+			if swag.Definitions[tc.wantPage] == nil {
+				failNow(t, "buildSwagDocument get a wrong named definition")
+			}
+			oas3, err := buildOas3Document(doc)
+			testError(t, false, err, "buildOas3Document")
+			if s := oas3.Components.Schemas[tc.wantResult]; s == nil || s.Properties.MustGet("data").(*oas3Schema).Ref != "#/components/schemas/"+tc.wantPage {
+				failNow(t, "buildOas3Document get a wrong named definition")
+			}
+			bs, err := doc.GenerateApib()
+			testError(t, false, err, "GenerateApib")
+			if !strings.Contains(string(bs), "## "+tc.wantResult+" (object)") || !strings.Contains(string(bs), "+ data ("+tc.wantPage+", required)") {
+				failNow(t, "GenerateApib get a wrong named data structure")
+			}
+		})
+	}
+
+	_, err := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		Option(NewOption().GenericNaming(OfGenericNaming)).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "_Result<UserDto>"))).
+		AddDefinitions(
+			NewDefinition("_Result", "").Generics("T").AddProperties(NewProperty("data", "T", true, "")),
+			NewDefinition("UserDto", "").AddProperties(NewProperty("id", "integer", true, "")),
+			NewDefinition("ResultOfUserDto", "").AddProperties(NewProperty("id", "integer", true, "")),
+		).
+		GenerateSwaggerJson()
+	testError(t, true, err, "GenerateSwaggerJson")
+}
+
+func TestGenerateRecursiveDefinition(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").
+			AddResponses(NewResponse(200, "TreeNode<Employee>"), NewResponse(201, "Comment<string>"))).
+		AddDefinitions(
+			NewDefinition("TreeNode", "").Generics("T").AddProperties(
+				NewProperty("value", "T", true, ""),
+				NewProperty("children", "TreeNode<T>[]", true, ""),
+			),
+			NewDefinition("Comment", "").Generics("T").AddProperties(
+				NewProperty("content", "T", true, ""),
+				NewProperty("thread", "Thread<T>", false, ""),
+			),
+			NewDefinition("Thread", "").Generics("T").AddProperties(NewProperty("replies", "Comment<T>[]", true, "")),
+			NewDefinition("Employee", "").AddProperties(
+				NewProperty("name", "string", true, ""),
+				NewProperty("manager", "Employee", false, ""),
+			),
+		)
+
+	swag, err := buildSwagDocument(doc)
+	testError(t, false, err, "buildSwagDocument")
+	if len(swag.Definitions) != 4 {
+		failNow(t, "buildSwagDocument get a wrong definition count")
+	}
+	if d := swag.Definitions["TreeNode<Employee>"]; d == nil || d.Properties.MustGet("children").(*swagSchema).Items.Ref != "#/definitions/TreeNode<Employee>" {
+		failNow(t, "buildSwagDocument get a wrong recursive definition")
+	}
+	if d := swag.Definitions["Thread<string>"]; d == nil || d.Properties.MustGet("replies").(*swagSchema).Items.Ref != "#/definitions/Comment<string>" {
+		failNow(t, "buildSwagDocument get a wrong mutually recursive definition")
+	}
+	oas3, err := buildOas3Document(doc)
+	testError(t, false, err, "buildOas3Document")
+	if s := oas3.Components.Schemas["Comment<string>"]; s == nil || s.Properties.MustGet("thread").(*oas3Schema).Ref != "#/components/schemas/Thread<string>" {
+		failNow(t, "buildOas3Document get a wrong mutually recursive definition")
+	}
+	bs, err := doc.GenerateApib()
+	testError(t, false, err, "GenerateApib")
+	if !strings.Contains(string(bs), "+ children (array[TreeNode<Employee>], required)") {
+		failNow(t, "GenerateApib get a wrong recursive definition")
+	}
+
+	_, err = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+		AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, "Node<string>"))).
+		AddDefinitions(NewDefinition("Node", "").Generics("T").AddProperties(NewProperty("next", "Node<T[]>", false, ""))).
+		GenerateSwaggerJson()
+	testError(t, true, err, "GenerateSwaggerJson")
+
+	for _, tc := range []struct {
+		name        string
+		giveType    string
+		giveDefs    []*Definition
+		wantErr     bool
+		wantDefName string
+	}{
+		{"deep nesting", strings.Repeat("Box<", 40) + "string" + strings.Repeat(">", 40),
+			[]*Definition{NewDefinition("Box", "").Generics("T").AddProperties(NewProperty("value", "T", true, ""))},
+			false, strings.Repeat("Box<", 40) + "string" + strings.Repeat(">", 40)},
+		{"fixed argument", "Tree<User>",
+			[]*Definition{
+				NewDefinition("Tree", "").Generics("T").AddProperties(NewProperty("value", "T", true, ""), NewProperty("pages", "Tree<Page<User>>", false, "")),
+				NewDefinition("Page", "").Generics("T").AddProperties(NewProperty("data", "T[]", true, "")),
+				NewDefinition("User", ""),
+			}, false, "Tree<Page<User>>"},
+		{"doubling argument", "Node<string>",
+			[]*Definition{
+				NewDefinition("Node", "").Generics("T").AddProperties(NewProperty("next", "Node<Pair<T, T>>", false, "")),
+				NewDefinition("Pair", "").Generics("T", "U").AddProperties(NewProperty("first", "T", true, ""), NewProperty("second", "U", true, "")),
+			}, true, ""},
+		{"swapping argument", "Pair<string, integer>",
+			[]*Definition{NewDefinition("Pair", "").Generics("T", "U").AddProperties(NewProperty("next", "Pair<U, T[]>", false, ""))},
+			true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).
+				AddOperations(NewOperation("get", "/", "s").AddResponses(NewResponse(200, tc.giveType))).
+				AddDefinitions(tc.giveDefs...)
+			swag, err := buildSwagDocument(doc)
+			testError(t, tc.wantErr, err, "buildSwagDocument")
+			if !tc.wantErr && swag.Definitions[tc.wantDefName] == nil {
+				failNow(t, "buildSwagDocument does not specialize definition "+tc.wantDefName)
+			}
+		})
+	}
+}
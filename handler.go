@@ -0,0 +1,189 @@
+package goapidoc
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// =============
+// HandlerOption
+// =============
+
+// HandlerOption represents an option of the http.Handler returned by Document.Handler.
+type HandlerOption struct {
+	ui         string
+	title      string
+	regenerate bool
+}
+
+// NewHandlerOption creates a default HandlerOption, which serves the bundled Swagger UI with try-it-out.
+func NewHandlerOption() *HandlerOption {
+	return &HandlerOption{ui: SWAGGER_UI}
+}
+
+// GetUI returns the ui from HandlerOption.
+func (h *HandlerOption) GetUI() string { return h.ui }
+
+// GetTitle returns the page title from HandlerOption.
+func (h *HandlerOption) GetTitle() string { return h.title }
+
+// GetRegenerate returns the regenerate flag from HandlerOption.
+func (h *HandlerOption) GetRegenerate() bool { return h.regenerate }
+
+// UI sets the ui in HandlerOption, supports SWAGGER_UI, SWAGGER_UI_READONLY and NO_UI.
+func (h *HandlerOption) UI(ui string) *HandlerOption {
+	h.ui = ui
+	return h
+}
+
+// Title sets the page title in HandlerOption, the title of Document's info is used if it is empty.
+func (h *HandlerOption) Title(title string) *HandlerOption {
+	h.title = title
+	return h
+}
+
+// Regenerate sets the regenerate flag in HandlerOption, the documents will be regenerated for each request if it is true, otherwise they
+// will be generated once when the first request comes.
+func (h *HandlerOption) Regenerate(regenerate bool) *HandlerOption {
+	h.regenerate = regenerate
+	return h
+}
+
+// =======
+// handler
+// =======
+
+// Handler returns an http.Handler which serves the generated documents in `/swagger.json`, `/swagger.yaml`, `/openapi.json`,
+// `/openapi.yaml` and `/api.apib`, and a fully offline Swagger UI page in `/` (or `/index.html`) whose assets are bundled in this package,
+// nil option means NewHandlerOption(). The handler can be mounted with a prefix by http.StripPrefix, such as
+// `http.Handle("/docs/", http.StripPrefix("/docs", doc.Handler(nil)))`.
+func (d *Document) Handler(opt *HandlerOption) http.Handler {
+	if opt == nil {
+		opt = NewHandlerOption()
+	}
+	return &documentHandler{doc: d, opt: opt}
+}
+
+// Handler returns an http.Handler which serves the generated documents of global Document and an offline api document page.
+func Handler(opt *HandlerOption) http.Handler {
+	return _document.Handler(opt)
+}
+
+// documentHandler is the http.Handler returned by Document.Handler.
+type documentHandler struct {
+	doc *Document
+	opt *HandlerOption
+
+	mu    sync.Mutex
+	cache map[string][]byte // path -> generated document
+}
+
+var handlerContentTypes = map[string]string{
+	"/swagger.json": "application/json; charset=utf-8",
+	"/swagger.yaml": "application/x-yaml; charset=utf-8",
+	"/openapi.json": "application/json; charset=utf-8",
+	"/openapi.yaml": "application/x-yaml; charset=utf-8",
+	"/api.apib":     "text/plain; charset=utf-8",
+}
+
+// handlerAssets are the bundled Swagger UI assets, they are served only if the ui is not NO_UI.
+var handlerAssets = map[string]struct {
+	contentType string
+	content     string
+}{
+	"/swagger-ui-bundle.js":            {"application/javascript; charset=utf-8", swaggerUIBundleJs},
+	"/swagger-ui-standalone-preset.js": {"application/javascript; charset=utf-8", swaggerUIStandalonePresetJs},
+	"/swagger-ui.css":                  {"text/css; charset=utf-8", swaggerUICss},
+}
+
+func (h *documentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := r.URL.Path
+	if path == "" {
+		// mounted by http.StripPrefix without trailing slash, redirect to make relative urls work
+		http.Redirect(w, r, strings.SplitN(r.RequestURI, "?", 2)[0]+"/", http.StatusMovedPermanently)
+		return
+	}
+	if path == "/" || path == "/index.html" {
+		if h.opt.ui == NO_UI {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(h.renderPage()))
+		return
+	}
+	if asset, ok := handlerAssets[path]; ok && h.opt.ui != NO_UI {
+		w.Header().Set("Content-Type", asset.contentType)
+		_, _ = w.Write([]byte(asset.content))
+		return
+	}
+	contentType, ok := handlerContentTypes[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	bs, err := h.generate(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(bs)
+}
+
+// generate generates the document of given path, and caches it if regenerate flag is not set.
+func (h *documentHandler) generate(path string) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if bs, ok := h.cache[path]; ok && !h.opt.regenerate {
+		return bs, nil
+	}
+
+	var bs []byte
+	var err error
+	switch path {
+	case "/swagger.json":
+		bs, err = h.doc.GenerateSwaggerJson()
+	case "/swagger.yaml":
+		bs, err = h.doc.GenerateSwaggerYaml()
+	case "/openapi.json":
+		bs, err = h.doc.GenerateOpenAPI3Json()
+	case "/openapi.yaml":
+		bs, err = h.doc.GenerateOpenAPI3Yaml()
+	case "/api.apib":
+		bs, err = h.doc.GenerateApib()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if h.cache == nil {
+		h.cache = make(map[string][]byte, len(handlerContentTypes))
+	}
+	h.cache[path] = bs
+	return bs, nil
+}
+
+// renderPage renders the offline api document page.
+func (h *documentHandler) renderPage() string {
+	title := h.opt.title
+	if title == "" && h.doc.info != nil {
+		title = h.doc.info.title
+	}
+	if title == "" {
+		title = "API Document"
+	}
+	ui, _ := json.Marshal(h.opt.ui) // escapes <, > and & as well, safe to be put in the script
+	return strings.NewReplacer(
+		"{{TITLE}}", html.EscapeString(title),
+		"{{UI}}", string(ui),
+	).Replace(handlerPageHTML)
+}
@@ -0,0 +1,42 @@
+package goapidoc
+
+// handlerPageHTML is the fully offline api document page served by Document.Handler. It renders `swagger.json` and `openapi.json` next to
+// the page by the bundled Swagger UI, see handler_assets.go.
+const handlerPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{TITLE}}</title>
+<link rel="stylesheet" type="text/css" href="swagger-ui.css">
+<style>
+html { box-sizing: border-box; overflow-y: scroll; }
+*, *:before, *:after { box-sizing: inherit; }
+body { margin: 0; background: #fafafa; }
+</style>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="swagger-ui-bundle.js" charset="utf-8"></script>
+<script src="swagger-ui-standalone-preset.js" charset="utf-8"></script>
+<script>
+window.onload = function () {
+  var UI = {{UI}};
+  window.ui = SwaggerUIBundle({
+    urls: [
+      { url: "swagger.json", name: "Swagger 2.0" },
+      { url: "openapi.json", name: "OpenAPI 3.0" }
+    ],
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    supportedSubmitMethods: UI === "swagger-ui-readonly" ? [] : ["get", "put", "post", "delete", "options", "head", "patch", "trace"],
+    validatorUrl: null,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,113 @@
+package goapidoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	doc := NewDocument("localhost", "/", NewInfo("<Demo>", "desc", "1.0")).
+		Definitions(NewDefinition("User", "user").Properties(NewProperty("id", "integer#int64", true, "id"))).
+		Operations(NewGetOperation("/user", "get user").Responses(NewResponse(200, "User")))
+	mux := http.NewServeMux()
+	mux.Handle("/docs/", http.StripPrefix("/docs", doc.Handler(nil)))
+	mux.Handle("/readonly/", http.StripPrefix("/readonly", doc.Handler(NewHandlerOption().UI(SWAGGER_UI_READONLY).Title("Readonly"))))
+	mux.Handle("/none/", http.StripPrefix("/none", doc.Handler(NewHandlerOption().UI(NO_UI))))
+	mux.Handle("/docs", http.StripPrefix("/docs", doc.Handler(nil)))
+
+	for _, tc := range []struct {
+		giveMethod   string
+		givePath     string
+		wantCode     int
+		wantType     string
+		wantContains string
+	}{
+		{"GET", "/docs/swagger.json", 200, "application/json", `"User"`},
+		{"HEAD", "/docs/swagger.json", 200, "application/json", ""},
+		{"GET", "/docs/swagger.yaml", 200, "application/x-yaml", "swagger: \"2.0\""},
+		{"GET", "/docs/openapi.json", 200, "application/json", `"openapi": "3.0.3"`},
+		{"GET", "/docs/openapi.yaml", 200, "application/x-yaml", "openapi: 3.0.3"},
+		{"GET", "/docs/api.apib", 200, "text/plain", "FORMAT: 1A"},
+		{"GET", "/docs/", 200, "text/html", "<title>&lt;Demo&gt;</title>"},
+		{"GET", "/docs/index.html", 200, "text/html", `var UI = "swagger-ui"`},
+		{"GET", "/docs/", 200, "text/html", `url: "swagger.json"`},
+		{"GET", "/docs/swagger-ui-bundle.js", 200, "application/javascript", "SwaggerUIBundle"},
+		{"GET", "/docs/swagger-ui-standalone-preset.js", 200, "application/javascript", "SwaggerUIStandalonePreset"},
+		{"GET", "/docs/swagger-ui.css", 200, "text/css", ".swagger-ui"},
+		{"GET", "/docs", 301, "", ""},
+		{"GET", "/docs/unknown", 404, "", ""},
+		{"POST", "/docs/swagger.json", 405, "", ""},
+		{"GET", "/readonly/", 200, "text/html", "<title>Readonly</title>"},
+		{"GET", "/readonly/", 200, "text/html", `var UI = "swagger-ui-readonly"`},
+		{"GET", "/none/", 404, "", ""},
+		{"GET", "/none/swagger-ui-bundle.js", 404, "", ""},
+		{"GET", "/none/swagger.json", 200, "application/json", `"User"`},
+	} {
+		t.Run(tc.giveMethod+" "+tc.givePath, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tc.giveMethod, tc.givePath, nil))
+			if rec.Code != tc.wantCode {
+				failNow(t, "Handler responds a wrong status code "+strconv.Itoa(rec.Code))
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), tc.wantType) {
+				failNow(t, "Handler responds a wrong content type "+rec.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(rec.Body.String(), tc.wantContains) {
+				failNow(t, "Handler responds a body without "+tc.wantContains)
+			}
+		})
+	}
+
+	t.Run("Page", func(t *testing.T) {
+		for _, path := range []string{"/docs/", "/readonly/"} {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			if strings.Contains(rec.Body.String(), "{{") || strings.Contains(rec.Body.String(), "http://") || strings.Contains(rec.Body.String(), "https://") {
+				failNow(t, "Handler responds a page with unreplaced placeholders or external assets")
+			}
+		}
+		rec := httptest.NewRecorder()
+		doc.Handler(NewHandlerOption().UI(`x"</script>`)).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if !strings.Contains(rec.Body.String(), `var UI = "x\"\u003c/script\u003e";`) {
+			failNow(t, "Handler responds a page with a wrongly encoded ui")
+		}
+	})
+
+	t.Run("Redirect", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", "/docs?x=1", nil))
+		if rec.Header().Get("Location") != "/docs/" {
+			failNow(t, "Handler redirects to a wrong location "+rec.Header().Get("Location"))
+		}
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		d := NewDocument("localhost", "/", NewInfo("a", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "string")))
+		cached, regenerated := d.Handler(nil), d.Handler(NewHandlerOption().Regenerate(true))
+		get := func(h http.Handler) string {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", "/swagger.json", nil))
+			return rec.Body.String()
+		}
+		get(cached)
+		d.Info(NewInfo("b", "", "1.0"))
+		if !strings.Contains(get(cached), `"title": "a"`) {
+			failNow(t, "Handler without regenerate flag does not use the cached document")
+		}
+		if !strings.Contains(get(regenerated), `"title": "b"`) {
+			failNow(t, "Handler with regenerate flag does not regenerate the document")
+		}
+	})
+
+	t.Run("Generate error", func(t *testing.T) {
+		d := NewDocument("localhost", "/", NewInfo("a", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "Invalid<")))
+		rec := httptest.NewRecorder()
+		d.Handler(nil).ServeHTTP(rec, httptest.NewRequest("GET", "/swagger.json", nil))
+		if rec.Code != http.StatusInternalServerError {
+			failNow(t, "Handler responds a wrong status code for invalid document "+strconv.Itoa(rec.Code))
+		}
+	})
+}
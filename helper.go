@@ -124,3 +124,16 @@ func (l *orderedMap) MarshalYAML() (interface{}, error) {
 	}
 	return ms, nil
 }
+
+func (l *orderedMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	ms := yaml.MapSlice{}
+	if err := unmarshal(&ms); err != nil {
+		return err
+	}
+	l.m = make(map[string]interface{}, len(ms))
+	l.i = make([]string, 0, len(ms))
+	for _, item := range ms {
+		l.Set(fmt.Sprintf("%v", item.Key), item.Value) // value is kept as yaml.MapSlice
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package goapidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ====================
+// ValidationMiddleware
+// ====================
+
+// RequestValidationError represents the response body of ValidationMiddleware when the request does not match the Document.
+type RequestValidationError struct {
+	Message string        `json:"message"`
+	Issues  []*ValueIssue `json:"issues"`
+}
+
+// NewValidationMiddleware returns a middleware which matches the request to an Operation of given Document by method and route, and checks
+// the path, query, header and form params and the json body against the params and definitions, responds 400 with RequestValidationError
+// if the request is invalid, or 413 if the body to check is larger than 32 MB. The requests which match no operation are passed to the
+// next handler directly. Notes that the Document is prehandled when the middleware is created, and DocumentError is returned if the
+// Document is invalid, so it should not be modified after that.
+func NewValidationMiddleware(doc *Document) (func(http.Handler) http.Handler, error) {
+	validator, err := newRequestValidator(doc)
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			issues, code := validator.validate(r)
+			if code != 0 && code != http.StatusOK {
+				writeValidationError(w, code, issues)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// ValidationMiddleware is the same as NewValidationMiddleware, but panics with DocumentError if the Document is invalid.
+func ValidationMiddleware(doc *Document) func(http.Handler) http.Handler {
+	middleware, err := NewValidationMiddleware(doc)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
+
+// writeValidationError responds given status code with RequestValidationError in json.
+func writeValidationError(w http.ResponseWriter, code int, issues []*ValueIssue) {
+	message := "Request validation failed"
+	if code == http.StatusRequestEntityTooLarge {
+		message = "Request body is too large"
+	}
+	bs, _ := json.Marshal(&RequestValidationError{Message: message, Issues: issues})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(bs)
+}
+
+// ValidateRequest matches the request to an Operation of Document and checks the request against the params and definitions, returns nil
+// issues if the request matches no operation. Notes that the request body is read and restored, and the Document is prehandled in every
+// call, so this is designed for tests, NewValidationMiddleware should be used in servers.
+func (d *Document) ValidateRequest(r *http.Request) ([]*ValueIssue, error) {
+	validator, err := newRequestValidator(d)
+	if err != nil {
+		return nil, err
+	}
+	issues, _ := validator.validate(r)
+	return issues, nil
+}
+
+// ValidateRequest matches the request to an Operation of global Document and checks the request against the params and definitions.
+func ValidateRequest(r *http.Request) ([]*ValueIssue, error) {
+	return _document.ValidateRequest(r)
+}
+
+// ================
+// requestValidator
+// ================
+
+// requestValidator represents a validator which checks the requests against the operations of Document.
+type requestValidator struct {
+	defMap map[string]*Definition
+	naming GenericNamingFunc
+	routes []*requestRoute
+}
+
+// requestRoute represents an operation with its route pattern and params (including the global ones).
+type requestRoute struct {
+	operation    *Operation
+	pattern      *regexp.Regexp
+	placeholders []string
+	params       []*Param
+}
+
+// defaultMaxMemory is the max memory used to parse multipart form, which is the same as http.Request.ParseMultipartForm.
+const defaultMaxMemory = 32 << 20 // 32 MB
+
+func newRequestValidator(doc *Document) (*requestValidator, error) {
+	checker, err := newValueChecker(doc, true)
+	if err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimSuffix(doc.basePath, "/")
+	v := &requestValidator{defMap: checker.defMap, naming: checker.naming, routes: make([]*requestRoute, 0, len(doc.operations))}
+	for _, op := range doc.operations {
+		route := &requestRoute{operation: op, params: op.params}
+		sb := strings.Builder{}
+		sb.WriteString("^" + regexp.QuoteMeta(basePath))
+		last := 0
+		for _, idx := range routeParamRe.FindAllStringSubmatchIndex(op.route, -1) {
+			sb.WriteString(regexp.QuoteMeta(op.route[last:idx[0]]) + "([^/]+)")
+			route.placeholders = append(route.placeholders, op.route[idx[2]:idx[3]])
+			last = idx[1]
+		}
+		sb.WriteString(regexp.QuoteMeta(op.route[last:]) + "$")
+		route.pattern = regexp.MustCompile(sb.String())
+		if opt := doc.option; opt != nil {
+			route.params = operationParams(op, opt.globalParams)
+		}
+		v.routes = append(v.routes, route)
+	}
+	return v, nil
+}
+
+// match finds the operation route of given request, the route with the least placeholders is preferred, such as `/user/me` is preferred
+// to `/user/{id}`.
+func (v *requestValidator) match(r *http.Request) (*requestRoute, map[string]string) {
+	var matched *requestRoute
+	var values []string
+	for _, route := range v.routes {
+		if !strings.EqualFold(route.operation.method, r.Method) {
+			continue
+		}
+		if matched != nil && len(route.placeholders) >= len(matched.placeholders) {
+			continue
+		}
+		if m := route.pattern.FindStringSubmatch(r.URL.EscapedPath()); m != nil {
+			matched, values = route, m[1:]
+		}
+	}
+	if matched == nil {
+		return nil, nil
+	}
+	pathValues := make(map[string]string, len(values))
+	for idx, name := range matched.placeholders {
+		value, err := url.PathUnescape(values[idx])
+		if err != nil {
+			value = values[idx]
+		}
+		pathValues[name] = value
+	}
+	return matched, pathValues
+}
+
+// validate checks given request, and returns the issues and the status code to respond, which is 0 if the request matches no operation,
+// http.StatusOK if the request is valid, http.StatusRequestEntityTooLarge if the body is too large to check, or http.StatusBadRequest.
+func (v *requestValidator) validate(r *http.Request) ([]*ValueIssue, int) {
+	route, pathValues := v.match(r)
+	if route == nil {
+		return nil, 0
+	}
+	c := &valueChecker{defMap: v.defMap, naming: v.naming, request: true}
+
+	// read and restore request body, only when the body is declared
+	hasBody, hasForm := false, false
+	for _, param := range route.params {
+		hasBody = hasBody || param.in == BODY
+		hasForm = hasForm || param.in == FORM
+	}
+	var body []byte
+	if (hasBody || hasForm) && r.Body != nil && r.Body != http.NoBody {
+		var complete bool
+		var err error
+		body, r.Body, complete, err = readLimitedBody(r.Body)
+		if err != nil {
+			c.report(BODY, "Request body cannot be read")
+			return c.issues, http.StatusBadRequest
+		}
+		if !complete {
+			c.report(BODY, "Request body is larger than 32 MB")
+			return c.issues, http.StatusRequestEntityTooLarge
+		}
+	}
+
+	var form url.Values
+	var files map[string]int
+	if hasForm {
+		form, files = v.parseForm(c, r, body)
+	}
+
+	for _, param := range route.params {
+		loc := param.in + "." + param.name
+		switch param.in {
+		case PATH:
+			v.checkParam(c, loc, param, []string{pathValues[param.name]}, true)
+		case QUERY:
+			values, ok := r.URL.Query()[param.name]
+			v.checkParam(c, loc, param, values, ok)
+		case HEADER:
+			values, ok := r.Header[http.CanonicalHeaderKey(param.name)]
+			v.checkParam(c, loc, param, values, ok)
+		case FORM:
+			if !hasForm {
+				continue
+			}
+			if strings.HasPrefix(param.typ, FILE) {
+				if files[param.name] == 0 && param.required {
+					c.report(loc, "Required param is missing")
+				}
+				continue
+			}
+			values, ok := form[param.name]
+			v.checkParam(c, loc, param, values, ok)
+		case BODY:
+			if hasBody {
+				v.checkBody(c, r, param, body)
+			}
+		}
+	}
+	if len(c.issues) > 0 {
+		return c.issues, http.StatusBadRequest
+	}
+	return nil, http.StatusOK
+}
+
+// readLimitedBody reads at most defaultMaxMemory bytes from given body, returns the read bytes, the restored body and a flag which represents
+// whether the whole body is read.
+func readLimitedBody(body io.ReadCloser) ([]byte, io.ReadCloser, bool, error) {
+	bs, err := ioutil.ReadAll(io.LimitReader(body, defaultMaxMemory+1))
+	if err != nil || len(bs) <= defaultMaxMemory {
+		_ = body.Close()
+		return bs, ioutil.NopCloser(bytes.NewReader(bs)), err == nil, err
+	}
+	restored := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(bs), body), body}
+	return bs, restored, false, nil
+}
+
+// parseForm parses the urlencoded or multipart form from given request body, returns the form values and the count of files, notes that the
+// file contents are skipped and not stored.
+func (v *requestValidator) parseForm(c *valueChecker, r *http.Request, body []byte) (url.Values, map[string]int) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case URL:
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			c.report(FORM, "Request form is invalid")
+			return nil, nil
+		}
+		return form, nil
+	case MPFD:
+		form, files := url.Values{}, make(map[string]int)
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				c.report(FORM, "Request form is invalid")
+				return nil, nil
+			}
+			name := part.FormName()
+			if name == "" {
+				continue
+			}
+			if part.FileName() != "" {
+				files[name]++ // file content is skipped
+				continue
+			}
+			value, err := ioutil.ReadAll(part)
+			if err != nil {
+				c.report(FORM, "Request form is invalid")
+				return nil, nil
+			}
+			form.Add(name, string(value))
+		}
+		return form, files
+	}
+	return nil, nil
+}
+
+// checkParam checks the values of a non-body param, the values are split by the collection format if the param is an array.
+func (v *requestValidator) checkParam(c *valueChecker, loc string, param *Param, values []string, ok bool) {
+	if !ok || len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		if ok && param.required && !param.allowEmpty {
+			c.report(loc, "Required param must not be empty")
+		} else if !ok && param.required {
+			c.report(loc, "Required param is missing")
+		}
+		return
+	}
+	at, err := parseApiType(param.typ)
+	if err != nil {
+		c.report(loc, err.Error())
+		return
+	}
+	rule := ruleOfParam(param)
+	c.checkType(loc, c.paramValue(values, at, rule, param.collectionFormat), at, rule)
+}
+
+// checkBody checks the json request body, the body in other content types is not checked.
+func (v *requestValidator) checkBody(c *valueChecker, r *http.Request, param *Param, body []byte) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		if param.required {
+			c.report(BODY, "Required request body is missing")
+		}
+		return
+	}
+	if !isJsonContentType(r.Header.Get("Content-Type")) {
+		return
+	}
+	value, err := decodeJson(body)
+	if err != nil {
+		c.report(BODY, "Request body is not a valid json")
+		return
+	}
+	c.checkValue(BODY, value, param.typ, ruleOfParam(param), param.inlineObject)
+}
@@ -0,0 +1,221 @@
+package goapidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidationMiddleware(t *testing.T) {
+	doc := NewDocument("localhost", "/api", NewInfo("test", "", "1.0")).
+		Option(NewOption().GenericNaming(OfGenericNaming).GlobalParams(NewHeaderParam("X-Version", "integer", false, "version").Minimum(1))).
+		Definitions(
+			NewDefinition("Gender", "gender").EnumType("string").Enum("male", "female"),
+			NewDefinition("Result", "result").Generics("T").Properties(
+				NewProperty("code", "integer#int32", true, "code"),
+				NewProperty("data", "T", true, "data"),
+			),
+			NewDefinition("User", "user").Properties(
+				NewProperty("id", "integer#int64", true, "id").ReadOnly(true),
+				NewProperty("name", "string", true, "name").MinLength(2).MaxLength(8),
+				NewProperty("email", "string#email", false, "email"),
+				NewProperty("gender", "Gender", false, "gender"),
+				NewProperty("tags", "string[]", false, "tags").MaxItems(2).UniqueItems(true),
+				NewProperty("nickname", "string?", false, "nickname"),
+				NewProperty("extra", "map<string, integer>", false, "extra"),
+				NewProperty("contact", "string|integer", false, "contact"),
+				NewProperty("address", "object", false, "address").InlineObject(NewInlineObject(
+					NewProperty("city", "string", true, "city"),
+				)),
+			),
+		).
+		Operations(
+			NewGetOperation("/user/{id}", "get user").
+				Params(NewPathParam("id", "integer#int64", true, "id").Minimum(1)).
+				Responses(NewResponse(200, "Result<User>")),
+			NewGetOperation("/user/me", "get me").
+				Responses(NewResponse(200, "Result<User>")),
+			NewGetOperation("/users", "query users").
+				Params(
+					NewQueryParam("page", "integer", true, "page").Minimum(1),
+					NewQueryParam("order", "string", false, "order").Enum("asc", "desc"),
+					NewQueryParam("ids", "integer[]", false, "ids").MaxItems(3),
+					NewQueryParam("genders", "Gender[]", false, "genders").CollectionFormat(MULTI),
+					NewQueryParam("q", "string", false, "q").Pattern("^[a-z]+$"),
+				).
+				Responses(NewResponse(200, "Result<User[]>")),
+			NewPostOperation("/user", "create user").
+				Params(NewBodyParam("body", "User", true, "user")).
+				Responses(NewResponse(200, "Result<User>")),
+			NewPutOperation("/user/{id}", "update user").
+				Params(NewPathParam("id", "integer#int64", true, "id"), NewBodyParam("body", "Result<User>", true, "user")).
+				Responses(NewResponse(200, "Result<User>")),
+			NewPostOperation("/avatar", "upload avatar").
+				Params(
+					NewFormParam("name", "string", true, "name").MinLength(1),
+					NewFormParam("file", "file", true, "file"),
+				).
+				Responses(NewResponse(200, "string")),
+			NewPostOperation("/login", "login").
+				Params(NewFormParam("username", "string", true, "username"), NewFormParam("age", "integer", false, "age")).
+				Responses(NewResponse(200, "string")),
+		)
+
+	middleware, err := NewValidationMiddleware(doc)
+	if err != nil {
+		failNow(t, "NewValidationMiddleware failed: "+err.Error())
+	}
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	multipartBody := func(name string, withFile bool) (string, string) {
+		buf := &bytes.Buffer{}
+		mw := multipart.NewWriter(buf)
+		_ = mw.WriteField("name", name)
+		if withFile {
+			fw, _ := mw.CreateFormFile("file", "a.png")
+			_, _ = fw.Write([]byte("png"))
+		}
+		_ = mw.Close()
+		return buf.String(), mw.FormDataContentType()
+	}
+	fullBody, fullType := multipartBody("a", true)
+	emptyBody, emptyType := multipartBody("", false)
+
+	for _, tc := range []struct {
+		giveMethod string
+		giveUrl    string
+		giveHeader map[string]string
+		giveBody   string
+		wantCode   int
+		wantIssues []string
+	}{
+		// not matched
+		{"GET", "/api/unknown", nil, "", 200, nil},
+		{"GET", "/user/1", nil, "", 200, nil},
+		{"PATCH", "/api/user/1", nil, "", 200, nil},
+
+		// path and header params
+		{"GET", "/api/user/1", nil, "", 200, nil},
+		{"GET", "/api/user/me", nil, "", 200, nil},
+		{"GET", "/api/user/0", nil, "", 400, []string{"path.id"}},
+		{"GET", "/api/user/abc", nil, "", 400, []string{"path.id"}},
+		{"GET", "/api/user/1%2F2", nil, "", 400, []string{"path.id"}},
+		{"GET", "/api/user/1", map[string]string{"X-Version": "2"}, "", 200, nil},
+		{"GET", "/api/user/1", map[string]string{"X-Version": "0"}, "", 400, []string{"header.X-Version"}},
+
+		// query params
+		{"GET", "/api/users?page=1&order=asc&ids=1,2&genders=male&genders=female&q=abc", nil, "", 200, nil},
+		{"GET", "/api/users", nil, "", 400, []string{"query.page"}},
+		{"GET", "/api/users?page=", nil, "", 400, []string{"query.page"}},
+		{"GET", "/api/users?page=1.5&order=random", nil, "", 400, []string{"query.page", "query.order"}},
+		{"GET", "/api/users?page=1&ids=1,x,3,4", nil, "", 400, []string{"query.ids", "query.ids[1]"}},
+		{"GET", "/api/users?page=1&genders=male&genders=unknown", nil, "", 400, []string{"query.genders[1]"}},
+		{"GET", "/api/users?page=1&q=ABC", nil, "", 400, []string{"query.q"}},
+
+		// json body
+		{"POST", "/api/user", nil, `{"name": "alice", "email": "alice@example.com", "gender": "female", "tags": ["a", "b"], "nickname": null,
+			"extra": {"age": 18}, "contact": 123, "address": {"city": "Tokyo"}}`, 200, nil},
+		{"POST", "/api/user", nil, "", 400, []string{"body"}},
+		{"POST", "/api/user", nil, `{"name": `, 400, []string{"body"}},
+		{"POST", "/api/user", nil, `[]`, 400, []string{"body"}},
+		{"POST", "/api/user", nil, `{}`, 400, []string{"body.name"}},
+		{"POST", "/api/user", nil, `{"id": "x", "name": "a"}`, 400, []string{"body.id", "body.name"}},
+		{"POST", "/api/user", nil, `{"name": "alice", "email": "alice", "gender": "other"}`, 400, []string{"body.email", "body.gender"}},
+		{"POST", "/api/user", nil, `{"name": "alice", "tags": ["a", "a", "b"], "nickname": 1}`, 400, []string{"body.tags", "body.tags", "body.nickname"}},
+		{"POST", "/api/user", nil, `{"name": "alice", "extra": {"age": "18"}, "contact": true}`, 400, []string{"body.extra.age", "body.contact"}},
+		{"POST", "/api/user", nil, `{"name": "alice", "address": {}}`, 400, []string{"body.address.city"}},
+		{"POST", "/api/user", map[string]string{"Content-Type": "application/xml"}, `<user></user>`, 200, nil},
+		{"PUT", "/api/user/1", nil, `{"code": 0, "data": {"name": "alice"}}`, 200, nil},
+		{"PUT", "/api/user/1", nil, `{"code": 0.5, "data": {}}`, 400, []string{"body.code", "body.data.name"}},
+
+		// form params
+		{"POST", "/api/avatar", map[string]string{"Content-Type": fullType}, fullBody, 200, nil},
+		{"POST", "/api/avatar", map[string]string{"Content-Type": emptyType}, emptyBody, 400, []string{"formData.name", "formData.file"}},
+		{"POST", "/api/login", map[string]string{"Content-Type": URL}, "username=alice&age=18", 200, nil},
+		{"POST", "/api/login", map[string]string{"Content-Type": URL}, "age=x", 400, []string{"formData.username", "formData.age"}},
+	} {
+		t.Run(tc.giveMethod+" "+tc.giveUrl, func(t *testing.T) {
+			req := httptest.NewRequest(tc.giveMethod, tc.giveUrl, strings.NewReader(tc.giveBody))
+			for k, v := range tc.giveHeader {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.wantCode {
+				failNow(t, "ValidationMiddleware responds a wrong status code "+strconv.Itoa(rec.Code)+": "+rec.Body.String())
+			}
+			if tc.wantCode != http.StatusBadRequest {
+				return
+			}
+			result := &RequestValidationError{}
+			if err := json.Unmarshal(rec.Body.Bytes(), result); err != nil {
+				failNow(t, "ValidationMiddleware responds an invalid json: "+err.Error())
+			}
+			locations := make([]string, 0, len(result.Issues))
+			for _, issue := range result.Issues {
+				locations = append(locations, issue.Location)
+			}
+			testMatchElements(t, locations, tc.wantIssues, "issue locations", "wanted locations")
+		})
+	}
+
+	t.Run("Restore body", func(t *testing.T) {
+		body := `{"name": "alice"}`
+		req := httptest.NewRequest("POST", "/api/user", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		echo := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bs := &bytes.Buffer{}
+			_, _ = bs.ReadFrom(r.Body)
+			_, _ = w.Write(bs.Bytes())
+		}))
+		echo.ServeHTTP(rec, req)
+		if rec.Body.String() != body {
+			failNow(t, "ValidationMiddleware does not restore the request body")
+		}
+
+		// the large body is rejected without checking
+		large := `{"name": "` + strings.Repeat("x", defaultMaxMemory) + `"}`
+		rec = httptest.NewRecorder()
+		echo.ServeHTTP(rec, httptest.NewRequest("POST", "/api/user", strings.NewReader(large)))
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			failNow(t, "ValidationMiddleware responds a wrong status code for the large request body "+strconv.Itoa(rec.Code))
+		}
+	})
+
+	t.Run("Skip body", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/user/1", &failReader{})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			failNow(t, "ValidationMiddleware reads the request body of an operation without body params")
+		}
+	})
+
+	t.Run("Invalid document", func(t *testing.T) {
+		d := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "Unknown")))
+		middleware, err := NewValidationMiddleware(d)
+		testError(t, true, err, "NewValidationMiddleware")
+		if middleware != nil {
+			failNow(t, "NewValidationMiddleware returns a middleware for invalid document")
+		}
+		testPanic(t, true, func() { ValidationMiddleware(d) }, "ValidationMiddleware")
+		_, err = d.ValidateRequest(httptest.NewRequest("GET", "/", nil))
+		testError(t, true, err, "ValidateRequest")
+	})
+}
+
+// failReader is an io.Reader which always fails, used to check that the body is not read.
+type failReader struct{}
+
+func (f *failReader) Read([]byte) (int, error) {
+	return 0, errors.New("body should not be read")
+}
@@ -0,0 +1,165 @@
+package goapidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+// ==========
+// MockServer
+// ==========
+
+// NewMockServer returns an http.Handler which mocks the operations of given Document. It routes the requests by operation method and
+// route, validates the inputs like NewValidationMiddleware, and answers with the declared response example or a payload synthesized from
+// the response type (see Document.ExampleFor) of the first 2xx response. The request header MOCK_STATUS_HEADER (`X-Mock-Status`) can be used
+// to select another declared response, and the input validation is skipped in this case. Notes that the Document is prehandled when the
+// mock server is created, and DocumentError is returned if the Document is invalid, so it should not be modified after that.
+func NewMockServer(doc *Document) (http.Handler, error) {
+	validator, err := newRequestValidator(doc)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := newExampleBuilder(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &mockServer{doc: doc, validator: validator, builder: builder}, nil
+}
+
+// NewMockTestServer starts and returns an httptest.Server which serves the mock server of given Document, the caller should call Close
+// when finished. Like httptest.NewServer, it panics with DocumentError if the Document is invalid.
+func NewMockTestServer(doc *Document) *httptest.Server {
+	server, err := NewMockServer(doc)
+	if err != nil {
+		panic(err)
+	}
+	return httptest.NewServer(server)
+}
+
+// mockServer is the http.Handler returned by NewMockServer.
+type mockServer struct {
+	doc       *Document
+	validator *requestValidator
+	builder   *exampleBuilder
+}
+
+func (m *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, _ := m.validator.match(r)
+	if route == nil {
+		if allowed := m.allowedMethods(r); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeMockMessage(w, http.StatusMethodNotAllowed, "Method `"+r.Method+"` is not allowed")
+			return
+		}
+		writeMockMessage(w, http.StatusNotFound, "Route `"+r.URL.Path+"` is not found")
+		return
+	}
+	op := route.operation
+
+	// select response
+	var resp *Response
+	if status := r.Header.Get(MOCK_STATUS_HEADER); status != "" {
+		code, err := strconv.Atoi(status)
+		for _, rr := range op.responses {
+			if err == nil && rr.code == code {
+				resp = rr
+				break
+			}
+		}
+		if resp == nil {
+			writeMockMessage(w, http.StatusBadRequest, "Response code `"+status+"` is not declared")
+			return
+		}
+	} else {
+		if issues, code := m.validator.validate(r); code != http.StatusOK {
+			writeValidationError(w, code, issues)
+			return
+		}
+		for _, rr := range op.responses {
+			if rr.code >= 200 && rr.code < 300 {
+				resp = rr
+				break
+			}
+		}
+		if resp == nil && len(op.responses) > 0 {
+			resp = op.responses[0]
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	// build response
+	builder := &exampleBuilder{defMap: m.builder.defMap, naming: m.builder.naming, visiting: make(map[string]bool)}
+	for _, h := range resp.headers {
+		value := h.example
+		if value == nil {
+			value = builder.build(h.typ, &valueRule{}, nil, nil)
+		}
+		if value != nil {
+			w.Header().Set(h.name, fmt.Sprint(value))
+		}
+	}
+	var docProduces []string
+	if m.doc.option != nil {
+		docProduces = m.doc.option.produces
+	}
+	produce := firstMime(op.produces, docProduces)
+	body, ok := mockBody(resp, produce, builder)
+	if !ok {
+		w.WriteHeader(resp.code)
+		return
+	}
+	var bs []byte
+	if s, isString := body.(string); isString && !isJsonContentType(produce) {
+		bs = []byte(s)
+	} else {
+		produce = JSON
+		bs, _ = jsonMarshal(body)
+	}
+	w.Header().Set("Content-Type", produce+"; charset=utf-8")
+	w.WriteHeader(resp.code)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(bs)
+	}
+}
+
+// allowedMethods returns the methods of the operations whose route matches the request path.
+func (m *mockServer) allowedMethods(r *http.Request) []string {
+	out := make([]string, 0, 2)
+	for _, route := range m.validator.routes {
+		if route.pattern.MatchString(r.URL.EscapedPath()) {
+			out = append(out, strings.ToUpper(route.operation.method))
+		}
+	}
+	return out
+}
+
+// mockBody returns the declared response example in given mime (or the first example), or the payload synthesized from response type.
+func mockBody(resp *Response, produce string, builder *exampleBuilder) (interface{}, bool) {
+	if len(resp.examples) > 0 {
+		for _, e := range resp.examples {
+			if e.mime == produce {
+				return e.example, true
+			}
+		}
+		return resp.examples[0].example, true
+	}
+	if resp.typ == "" && resp.inlineObject == nil {
+		return nil, false
+	}
+	return builder.build(resp.typ, &valueRule{}, resp.inlineObject, nil), true
+}
+
+// writeMockMessage responds the message in json with given status code.
+func writeMockMessage(w http.ResponseWriter, code int, message string) {
+	bs, _ := json.Marshal(map[string]string{"message": message})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write(bs)
+}
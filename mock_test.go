@@ -0,0 +1,124 @@
+package goapidoc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMockServer(t *testing.T) {
+	doc := NewDocument("localhost", "/api", NewInfo("test", "", "1.0")).
+		Definitions(
+			NewDefinition("Result", "result").Generics("T").Properties(
+				NewProperty("code", "integer#int32", true, "code").Example(200),
+				NewProperty("data", "T", true, "data"),
+			),
+			NewDefinition("User", "user").Properties(
+				NewProperty("id", "integer#int64", true, "id").Example(1),
+				NewProperty("name", "string", true, "name").MinLength(2),
+			),
+		).
+		Operations(
+			NewGetOperation("/user/{id}", "get user").
+				Params(NewPathParam("id", "integer#int64", true, "id")).
+				Responses(
+					NewResponse(404, "Result<string>").Examples(NewResponseExample(JSON, map[string]interface{}{"code": 404, "data": "not found"})),
+					NewResponse(200, "Result<User>").Headers(NewResponseHeader("X-Request-Id", "string#uuid", "request id")),
+				),
+			NewPostOperation("/user", "create user").
+				Params(NewBodyParam("body", "User", true, "user")).
+				Responses(NewResponse(201, "User").Examples(NewResponseExample(JSON, map[string]interface{}{"id": 2, "name": "bob"}))),
+			NewDeleteOperation("/user/{id}", "delete user").
+				Params(NewPathParam("id", "integer#int64", true, "id")).
+				Responses(NewResponse(204, "")),
+			NewGetOperation("/ping", "ping").Produces(PLAIN).
+				Responses(NewResponse(200, "string").Examples(NewResponseExample(PLAIN, "pong"))),
+		)
+	server := NewMockTestServer(doc)
+	defer server.Close()
+
+	for _, tc := range []struct {
+		giveMethod string
+		givePath   string
+		giveStatus string
+		giveBody   string
+		wantCode   int
+		wantType   string
+		wantBody   string
+		wantHeader string
+	}{
+		{"GET", "/api/user/1", "", "", 200, JSON, `"data": {`, "3fa85f64-5717-4562-b3fc-2c963f66afa6"},
+		{"GET", "/api/user/1", "", "", 200, JSON, `"id": 1`, ""},
+		{"GET", "/api/user/1", "404", "", 404, JSON, `"data": "not found"`, ""},
+		{"GET", "/api/user/abc", "", "", 400, JSON, `"path.id"`, ""},
+		{"GET", "/api/user/abc", "404", "", 404, JSON, `"code": 404`, ""},
+		{"GET", "/api/user/1", "500", "", 400, JSON, "is not declared", ""},
+		{"POST", "/api/user", "", `{"id": 1, "name": "alice"}`, 201, JSON, `"name": "bob"`, ""},
+		{"POST", "/api/user", "", `{"id": 1, "name": "a"}`, 400, JSON, `"body.name"`, ""},
+		{"DELETE", "/api/user/1", "", "", 204, "", "", ""},
+		{"GET", "/api/ping", "", "", 200, PLAIN, "pong", ""},
+		{"PUT", "/api/user/1", "", "", 405, JSON, "is not allowed", ""},
+		{"GET", "/api/unknown", "", "", 404, JSON, "is not found", ""},
+	} {
+		t.Run(tc.giveMethod+" "+tc.givePath+" "+tc.giveStatus, func(t *testing.T) {
+			req, _ := http.NewRequest(tc.giveMethod, server.URL+tc.givePath, strings.NewReader(tc.giveBody))
+			if tc.giveStatus != "" {
+				req.Header.Set(MOCK_STATUS_HEADER, tc.giveStatus)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				failNow(t, "Request to mock server failed: "+err.Error())
+			}
+			defer resp.Body.Close()
+			bs, _ := ioutil.ReadAll(resp.Body)
+			if resp.StatusCode != tc.wantCode {
+				failNow(t, "Mock server responds a wrong status code "+strconv.Itoa(resp.StatusCode)+": "+string(bs))
+			}
+			if !strings.HasPrefix(resp.Header.Get("Content-Type"), tc.wantType) {
+				failNow(t, "Mock server responds a wrong content type "+resp.Header.Get("Content-Type"))
+			}
+			if !strings.Contains(string(bs), tc.wantBody) {
+				failNow(t, "Mock server responds a body without "+tc.wantBody+": "+string(bs))
+			}
+			if tc.wantHeader != "" && resp.Header.Get("X-Request-Id") != tc.wantHeader {
+				failNow(t, "Mock server responds a wrong header "+resp.Header.Get("X-Request-Id"))
+			}
+		})
+	}
+
+	t.Run("Responses conform to document", func(t *testing.T) {
+		checker, err := NewResponseChecker(doc)
+		if err != nil {
+			failNow(t, "NewResponseChecker failed: "+err.Error())
+		}
+		for _, tc := range []struct {
+			giveMethod string
+			giveRoute  string
+			givePath   string
+		}{
+			{GET, "/user/{id}", "/api/user/1"},
+			{GET, "/user/1", "/api/user/1"},
+			{DELETE, "/user/{id}", "/api/user/1"},
+		} {
+			req, _ := http.NewRequest(strings.ToUpper(tc.giveMethod), server.URL+tc.givePath, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				failNow(t, "Request to mock server failed: "+err.Error())
+			}
+			testError(t, false, checker.CheckResponse(tc.giveMethod, tc.giveRoute, resp), "CheckResponse")
+			_ = resp.Body.Close()
+		}
+	})
+
+	t.Run("Invalid document", func(t *testing.T) {
+		d := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).Operations(NewGetOperation("/", "root").Responses(NewResponse(200, "Unknown")))
+		server, err := NewMockServer(d)
+		testError(t, true, err, "NewMockServer")
+		if server != nil {
+			failNow(t, "NewMockServer returns a mock server for invalid document")
+		}
+		testPanic(t, true, func() { NewMockTestServer(d) }, "NewMockTestServer")
+	})
+}
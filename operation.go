@@ -277,6 +277,7 @@ type Response struct {
 	examples      []*ResponseExample
 	headers       []*ResponseHeader
 	additionalDoc string
+	inlineObject  *InlineObject
 }
 
 // NewResponse creates a default Response with given arguments.
@@ -302,6 +303,9 @@ func (r *Response) GetHeaders() []*ResponseHeader { return r.headers }
 // GetAdditionalDoc returns the additional document from Response
 func (r *Response) GetAdditionalDoc() string { return r.additionalDoc }
 
+// GetInlineObject returns the inline object from Response
+func (r *Response) GetInlineObject() *InlineObject { return r.inlineObject }
+
 // Code sets the code in Response.
 func (r *Response) Code(code int) *Response {
 	r.code = code
@@ -350,6 +354,12 @@ func (r *Response) AdditionalDoc(doc string) *Response {
 	return r
 }
 
+// InlineObject sets the inline object in Response, notes that the response type must be object or array of object, such as `object[]`.
+func (r *Response) InlineObject(inline *InlineObject) *Response {
+	r.inlineObject = inline
+	return r
+}
+
 // ===============
 // ResponseExample
 // ===============
@@ -466,6 +476,7 @@ type Param struct {
 	multipleOf       float64
 	itemOption       *ItemOption
 	xmlRepr          *XMLRepr
+	inlineObject     *InlineObject
 }
 
 // NewParam creates a default Param with given arguments.
@@ -567,6 +578,9 @@ func (p *Param) GetItemOption() *ItemOption { return p.itemOption }
 // GetXMLRepr returns the xml repr from Param.
 func (p *Param) GetXMLRepr() *XMLRepr { return p.xmlRepr }
 
+// GetInlineObject returns the inline object from Param.
+func (p *Param) GetInlineObject() *InlineObject { return p.inlineObject }
+
 // Name sets the name in Param.
 func (p *Param) Name(name string) *Param {
 	p.name = name
@@ -725,3 +739,10 @@ func (p *Param) XMLRepr(repr *XMLRepr) *Param {
 	p.xmlRepr = repr
 	return p
 }
+
+// InlineObject sets the inline object in Param, notes that this is only supported in body param, and the param type must be object or
+// array of object, such as `object[]`.
+func (p *Param) InlineObject(inline *InlineObject) *Param {
+	p.inlineObject = inline
+	return p
+}
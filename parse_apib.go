@@ -0,0 +1,963 @@
+package goapidoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseApib parses given API Blueprint 1A script and returns a new Document. Note that only the subset generated by GenerateApib
+// is supported, and some information (such as integer type, param name of body, global params) may be lost.
+func ParseApib(bs []byte) (*Document, error) {
+	text := strings.ReplaceAll(fastBtos(bs), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " \t")
+	}
+	return parseApibDocument(lines)
+}
+
+// LoadApib loads and parses given API Blueprint 1A file.
+func LoadApib(path string) (*Document, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseApib(bs)
+}
+
+var (
+	apibTitleRe      = regexp.MustCompile(`^# (.+) \((.+)\)$`)
+	apibGroupRe      = regexp.MustCompile(`^# Group (.+)$`)
+	apibResourceRe   = regexp.MustCompile(`^## (.*) \[(/.*)]$`)
+	apibActionRe     = regexp.MustCompile("^### (.*) \\[([A-Z]+)]$")
+	apibStructRe     = regexp.MustCompile(`^## (.+) \((object|enum\[.+])\)$`)
+	apibRawRouteRe   = regexp.MustCompile("^> `(?:[A-Z]+ )?(/.*)`$")
+	apibLinkRe       = regexp.MustCompile(`^\[(.*)]\((.+)\)$`)
+	apibTermsRe      = regexp.MustCompile(`^\[Terms of service]\((.+)\)$`)
+	apibLicenseRe    = regexp.MustCompile(`^\[License: (.+)]\((.+)\)$|^License: (.+)$`)
+	apibWebsiteRe    = regexp.MustCompile(`^\[(.+) - Website]\((.+)\)$`)
+	apibEmailRe      = regexp.MustCompile(`^\[Send email to (.+)]\(mailto:(.+)\)$`)
+	apibSecurityRe   = regexp.MustCompile(`^\+ ([^:]+): (apiKey|basic|oauth2)(?: - (.*))?$`)
+	apibSecOptionRe  = regexp.MustCompile(`^ {4}\+ (name|in|flow|authUrl|tokenUrl|scope): (.+)$`)
+	apibRequestRe    = regexp.MustCompile(`^\+ Request \((.+)\)$`)
+	apibResponseRe   = regexp.MustCompile(`^\+ Response (\d+) \((.+)\)$`)
+	apibAttributesRe = regexp.MustCompile(`^\+ Attributes \((.+)\)$`)
+	apibEntryRe      = regexp.MustCompile("^\\+ (\\S+?)(?:: `(.*?)`)? \\((.+?), (required|optional)(, nullable)?\\)(?: - (.*))?$")
+	apibHeaderRe     = regexp.MustCompile(`^(\S+): \((.+?), (required|optional)\)(?: - (.*))?$`)
+	apibHeaderExRe   = regexp.MustCompile(`^(\S+): (.*)$`)
+	apibDefaultRe    = regexp.MustCompile("^\\+ Default: `(.*)`$")
+	apibMemberRe     = regexp.MustCompile("^\\+ `(.*)`$")
+	apibMapMemberRe  = regexp.MustCompile(`^\+ \*key \((.+?)\)\* \((.+?)\)$`)
+	apibIncludeRe    = regexp.MustCompile(`^\+ Include (.+)$`)
+	apibUnionItemRe  = regexp.MustCompile(`^\+ \((.+)\)$`)
+	apibEnumMemberRe = regexp.MustCompile("^\\+ `(.*)`(?: - (.*))?$")
+)
+
+// ======================
+// lines & blocks helpers
+// ======================
+
+// apibIndent returns the count of leading spaces of given line.
+func apibIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// apibUnindent removes at most n leading spaces from all given lines.
+func apibUnindent(lines []string, n int) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if apibIndent(line) >= n {
+			out = append(out, line[n:])
+		} else {
+			out = append(out, strings.TrimLeft(line, " "))
+		}
+	}
+	return out
+}
+
+// apibParagraphs splits given lines into paragraphs which are separated by blank lines.
+func apibParagraphs(lines []string) []string {
+	out := make([]string, 0, 2)
+	curr := make([]string, 0, 2)
+	for _, line := range lines {
+		if line == "" {
+			if len(curr) > 0 {
+				out = append(out, strings.Join(curr, "\n"))
+				curr = curr[:0]
+			}
+			continue
+		}
+		curr = append(curr, line)
+	}
+	if len(curr) > 0 {
+		out = append(out, strings.Join(curr, "\n"))
+	}
+	return out
+}
+
+// apibSplitDocs splits given paragraphs into description and additional document, recognized paragraphs are consumed by given function.
+func apibSplitDocs(paragraphs []string, recognize func(p string) bool) (desc string, additionalDoc string) {
+	descs := make([]string, 0, 1)
+	additionalDocs := make([]string, 0, 1)
+	recognized := false
+	for _, p := range paragraphs {
+		if recognize != nil && recognize(p) {
+			recognized = true
+			continue
+		}
+		if recognized {
+			additionalDocs = append(additionalDocs, p)
+		} else {
+			descs = append(descs, p)
+		}
+	}
+	if !recognized && len(descs) > 1 {
+		descs, additionalDocs = descs[:1], descs[1:] // treat the first paragraph as desc
+	}
+	return strings.Join(descs, "\n\n"), strings.Join(additionalDocs, "\n\n")
+}
+
+// apibSplitBy splits given lines by the lines that match given function, and returns the leading lines and blocks.
+func apibSplitBy(lines []string, isHead func(line string) bool) (leading []string, blocks [][]string) {
+	start := -1
+	for idx, line := range lines {
+		if isHead(line) {
+			if start == -1 {
+				leading = lines[:idx]
+			} else {
+				blocks = append(blocks, lines[start:idx])
+			}
+			start = idx
+		}
+	}
+	if start == -1 {
+		return lines, nil
+	}
+	blocks = append(blocks, lines[start:])
+	return leading, blocks
+}
+
+// apibBody joins given body lines which are indented by 12 spaces.
+func apibBody(lines []string) string {
+	return strings.Trim(strings.Join(apibUnindent(lines, 12), "\n"), "\n")
+}
+
+// ============
+// type & entry
+// ============
+
+// parseApibType parses given API Blueprint type and format to type string, returns whether the type is enum.
+func parseApibType(typ, format string) (string, bool) {
+	if strings.HasPrefix(typ, "enum[") && strings.HasSuffix(typ, "]") {
+		t, _ := parseApibType(typ[5:len(typ)-1], format)
+		return t, true
+	}
+	if strings.HasPrefix(typ, "array[") && strings.HasSuffix(typ, "]") {
+		t, _ := parseApibType(typ[6:len(typ)-1], "")
+		return t + "[]", false
+	}
+	switch typ {
+	case NUMBER:
+		switch format {
+		case INT32:
+			return INTEGER, false
+		case INT64:
+			return INTEGER + "#" + INT64, false
+		case DOUBLE:
+			return NUMBER, false
+		}
+		return NUMBER + "#" + format, false // number# means no format
+	case STRING, BOOLEAN:
+		if format == "" {
+			return typ, false
+		}
+		return typ + "#" + format, false
+	}
+	return parseSafeDefinitionName(typ), false
+}
+
+// parseApibMembersType parses the nested members (without indent) of a map or union type to type string, returns false if there is no
+// such member. Notes that the options, default and enum members before the nested members will be skipped.
+func parseApibMembersType(lines []string) (string, bool) {
+	head := -1
+	for idx, line := range lines {
+		if line == "" || apibIndent(line) > 0 {
+			continue
+		}
+		if apibMapMemberRe.MatchString(line) || line == "+ One Of" {
+			head = idx
+			break
+		}
+		if strings.HasPrefix(line, "(") || line == "+ Members" || apibDefaultRe.MatchString(line) {
+			continue
+		}
+		return "", false
+	}
+	if head == -1 {
+		return "", false
+	}
+	children := make([]string, 0, len(lines)-head-1)
+	for _, line := range lines[head+1:] {
+		if line != "" && apibIndent(line) < 4 {
+			break
+		}
+		children = append(children, line)
+	}
+	children = apibUnindent(children, 4)
+
+	memberType := func(typ string, lines []string) string {
+		if t, ok := parseApibMembersType(lines); ok {
+			return t // nested map or union
+		}
+		t, _ := parseApibType(typ, "")
+		if t == NUMBER+"#" {
+			t = NUMBER // format of member is not supported
+		}
+		return t
+	}
+	if m := apibMapMemberRe.FindStringSubmatch(lines[head]); m != nil {
+		return MAP + "<" + memberType(m[1], nil) + ", " + memberType(m[2], children) + ">", true
+	}
+	_, blocks := apibSplitBy(children, func(line string) bool { return strings.HasPrefix(line, "+ ") })
+	items := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if m := apibIncludeRe.FindStringSubmatch(block[0]); m != nil {
+			items = append(items, parseSafeDefinitionName(m[1]))
+		} else if m := apibUnionItemRe.FindStringSubmatch(block[0]); m != nil {
+			items = append(items, memberType(m[1], apibUnindent(block[1:], 4)))
+		}
+	}
+	if len(items) < 2 {
+		return "", false
+	}
+	return strings.Join(items, "|"), true
+}
+
+// parseApibInlineObject parses the nested property entries (without indent) of an object or array of object type to inline object,
+// returns nil inline object if there is no such entry. Notes that the entries of array are wrapped in an object member.
+func parseApibInlineObject(apibTyp string, lines []string) (string, *InlineObject, error) {
+	typ, depth := apibTyp, 0
+	for strings.HasPrefix(typ, "array[") && strings.HasSuffix(typ, "]") {
+		typ, depth = typ[6:len(typ)-1], depth+1
+	}
+	if typ != OBJECT {
+		return "", nil, nil
+	}
+	if depth > 0 {
+		head := -1
+		for idx, line := range lines {
+			if line == "+ (object)" {
+				head = idx
+				break
+			}
+		}
+		if head == -1 {
+			return "", nil, nil
+		}
+		children := make([]string, 0, len(lines)-head-1)
+		for _, line := range lines[head+1:] {
+			if line != "" && apibIndent(line) < 4 {
+				break
+			}
+			children = append(children, line)
+		}
+		lines = apibUnindent(children, 4)
+	}
+
+	head := -1
+	for idx, line := range lines {
+		if apibEntryRe.MatchString(line) {
+			head = idx
+			break
+		}
+	}
+	if head == -1 {
+		if depth == 0 {
+			return "", nil, nil
+		}
+		return OBJECT + strings.Repeat("[]", depth), &InlineObject{}, nil
+	}
+	entries, err := parseApibEntries(lines[head:])
+	if err != nil {
+		return "", nil, err
+	}
+	inline := &InlineObject{properties: make([]*Property, 0, len(entries))}
+	for _, e := range entries {
+		inline.properties = append(inline.properties, parseApibProperty(e))
+	}
+	return OBJECT + strings.Repeat("[]", depth), inline, nil
+}
+
+var (
+	apibValueRangeRe  = regexp.MustCompile(`^(-?[\d.]+) (<=|<) val (<=|<) (-?[\d.]+)$`)
+	apibValueMinRe    = regexp.MustCompile(`^val (>=|>) (-?[\d.]+)$`)
+	apibValueMaxRe    = regexp.MustCompile(`^val (<=|<) (-?[\d.]+)$`)
+	apibLenRangeRe    = regexp.MustCompile(`^(\d+) <= (len|#items) <= (\d+)$`)
+	apibLenMinRe      = regexp.MustCompile(`^(len|#items) >= (\d+)$`)
+	apibLenMaxRe      = regexp.MustCompile(`^(len|#items) <= (\d+)$`)
+	apibFormatRe      = regexp.MustCompile(`^format: (.+)$`)
+	apibPatternRe     = regexp.MustCompile(`^pattern: /(.*)/$`)
+	apibCollectionRe  = regexp.MustCompile(`^collection format: (.+)$`)
+	apibMultipleOfRe  = regexp.MustCompile(`^multiple of (.+)$`)
+	apibOptionSplitRe = regexp.MustCompile(`, (?:format|allow empty value|pattern|unique items|collection format|multiple of|val |len |#items |-?[\d.]+ <)`)
+)
+
+// parseApibOptions parses the options line (without brackets) into given apibSchema, and returns the format.
+func parseApibOptions(schema *apibSchema, line string) (format string) {
+	// split options, notes that pattern may contain ", "
+	options := make([]string, 0, 2)
+	for {
+		loc := apibOptionSplitRe.FindStringIndex(line)
+		if loc == nil {
+			options = append(options, line)
+			break
+		}
+		options = append(options, line[:loc[0]])
+		line = line[loc[0]+2:]
+	}
+
+	setLen := func(kind string, min, max *int) {
+		if kind == "len" {
+			schema.minLength, schema.maxLength = min, max
+		} else {
+			schema.minItems, schema.maxItems = min, max
+		}
+	}
+	atoi := func(s string) *int {
+		i, _ := strconv.Atoi(s)
+		return &i
+	}
+	atof := func(s string) *float64 {
+		f, _ := strconv.ParseFloat(s, 64)
+		return &f
+	}
+	for _, opt := range options {
+		if m := apibFormatRe.FindStringSubmatch(opt); m != nil {
+			format = m[1]
+		} else if opt == "allow empty value" {
+			schema.allowEmpty = true
+		} else if m := apibPatternRe.FindStringSubmatch(opt); m != nil {
+			schema.pattern = m[1]
+		} else if m := apibLenRangeRe.FindStringSubmatch(opt); m != nil {
+			setLen(m[2], atoi(m[1]), atoi(m[3]))
+		} else if m := apibLenMinRe.FindStringSubmatch(opt); m != nil {
+			setLen(m[1], atoi(m[2]), nil)
+		} else if m := apibLenMaxRe.FindStringSubmatch(opt); m != nil {
+			setLen(m[1], nil, atoi(m[2]))
+		} else if opt == "unique items" {
+			schema.uniqueItems = true
+		} else if m := apibCollectionRe.FindStringSubmatch(opt); m != nil {
+			schema.collectionFormat = m[1]
+		} else if m := apibValueRangeRe.FindStringSubmatch(opt); m != nil {
+			schema.minimum, schema.exclusiveMin = atof(m[1]), m[2] == "<"
+			schema.maximum, schema.exclusiveMax = atof(m[4]), m[3] == "<"
+		} else if m := apibValueMinRe.FindStringSubmatch(opt); m != nil {
+			schema.minimum, schema.exclusiveMin = atof(m[2]), m[1] == ">"
+		} else if m := apibValueMaxRe.FindStringSubmatch(opt); m != nil {
+			schema.maximum, schema.exclusiveMax = atof(m[2]), m[1] == "<"
+		} else if m := apibMultipleOfRe.FindStringSubmatch(opt); m != nil {
+			schema.multipleOf = *atof(m[1])
+		}
+	}
+	return format
+}
+
+// parseApibEntries parses the MSON entries (without indent) into apibSchema slice.
+func parseApibEntries(lines []string) ([]*apibSchema, error) {
+	_, blocks := apibSplitBy(lines, func(line string) bool { return strings.HasPrefix(line, "+ ") })
+	out := make([]*apibSchema, 0, len(blocks))
+	for _, block := range blocks {
+		m := apibEntryRe.FindStringSubmatch(block[0])
+		if m == nil {
+			return nil, fmt.Errorf("invalid attribute `%s`", block[0])
+		}
+		schema := &apibSchema{name: m[1], required: m[4] == "required", nullable: m[5] != "", desc: m[6]}
+		apibTyp, format := m[3], ""
+		defaul, enum := "", make([]string, 0)
+		hasDefault, inMembers := false, false
+		for _, line := range apibUnindent(block[1:], 4) {
+			if line == "" {
+				continue
+			}
+			if inMembers {
+				if mm := apibMemberRe.FindStringSubmatch(strings.TrimLeft(line, " ")); mm != nil {
+					enum = append(enum, mm[1])
+					continue
+				}
+				inMembers = false
+			}
+			if strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")") {
+				format = parseApibOptions(schema, line[1:len(line)-1])
+			} else if mm := apibDefaultRe.FindStringSubmatch(line); mm != nil {
+				defaul, hasDefault = mm[1], true
+			} else if line == "+ Members" {
+				inMembers = true
+			}
+		}
+
+		typ, isEnum := parseApibType(apibTyp, format)
+		if apibTyp == OBJECT {
+			if membersTyp, ok := parseApibMembersType(apibUnindent(block[1:], 4)); ok {
+				typ = membersTyp
+			}
+		}
+		t, inline, err := parseApibInlineObject(apibTyp, apibUnindent(block[1:], 4))
+		if err != nil {
+			return nil, err
+		}
+		if inline != nil {
+			typ, schema.inline = t, inline
+		}
+		schema.typ = typ
+		if m[2] != "" {
+			schema.example = parsePrimeValue(m[2], typ)
+		}
+		if hasDefault {
+			schema.defaul = parsePrimeValue(defaul, typ)
+		}
+		if isEnum {
+			for _, e := range enum {
+				schema.enum = append(schema.enum, parsePrimeValue(e, typ))
+			}
+		}
+		out = append(out, schema)
+	}
+	return out, nil
+}
+
+// parseApibHeader parses a header line into apibSchema.
+func parseApibHeader(line string) (*apibSchema, error) {
+	if m := apibHeaderRe.FindStringSubmatch(line); m != nil {
+		typ, _ := parseApibType(m[2], "")
+		return &apibSchema{name: m[1], typ: typ, required: m[3] == "required", desc: m[4]}, nil
+	}
+	if m := apibHeaderExRe.FindStringSubmatch(line); m != nil {
+		return &apibSchema{name: m[1], typ: STRING, required: true, example: m[2]}, nil
+	}
+	return nil, fmt.Errorf("invalid header `%s`", line)
+}
+
+func parseApibParam(s *apibSchema, in string) *Param {
+	return &Param{
+		name:             s.name,
+		in:               in,
+		typ:              s.typ,
+		required:         s.required,
+		desc:             s.desc,
+		allowEmpty:       s.allowEmpty,
+		defaul:           s.defaul,
+		example:          s.example,
+		pattern:          s.pattern,
+		enum:             s.enum,
+		minLength:        s.minLength,
+		maxLength:        s.maxLength,
+		minItems:         s.minItems,
+		maxItems:         s.maxItems,
+		uniqueItems:      s.uniqueItems,
+		collectionFormat: s.collectionFormat,
+		minimum:          s.minimum,
+		maximum:          s.maximum,
+		exclusiveMin:     s.exclusiveMin,
+		exclusiveMax:     s.exclusiveMax,
+		multipleOf:       s.multipleOf,
+		inlineObject:     s.inline,
+	}
+}
+
+func parseApibProperty(s *apibSchema) *Property {
+	return &Property{
+		name:             s.name,
+		typ:              s.typ,
+		required:         s.required,
+		desc:             s.desc,
+		allowEmpty:       s.allowEmpty,
+		defaul:           s.defaul,
+		example:          s.example,
+		pattern:          s.pattern,
+		enum:             s.enum,
+		minLength:        s.minLength,
+		maxLength:        s.maxLength,
+		minItems:         s.minItems,
+		maxItems:         s.maxItems,
+		uniqueItems:      s.uniqueItems,
+		collectionFormat: s.collectionFormat,
+		minimum:          s.minimum,
+		maximum:          s.maximum,
+		exclusiveMin:     s.exclusiveMin,
+		exclusiveMax:     s.exclusiveMax,
+		multipleOf:       s.multipleOf,
+		inlineObject:     s.inline,
+		nullable:         s.nullable,
+	}
+}
+
+// ======================
+// request & response
+// ======================
+
+// apibPayload represents a parsed request or response section.
+type apibPayload struct {
+	mime       string
+	paragraphs []string
+	attrType   string
+	attrNested string // map, union or inline object type parsed from nested members
+	attrInline *InlineObject
+	attrs      []*apibSchema
+	headers    []*apibSchema
+	body       string
+}
+
+func parseApibPayload(mime string, lines []string) (*apibPayload, error) {
+	out := &apibPayload{mime: mime}
+	leading, blocks := apibSplitBy(lines, func(line string) bool {
+		return apibIndent(line) == 4 && strings.HasPrefix(line, "    + ")
+	})
+	out.paragraphs = apibParagraphs(apibUnindent(leading, 4))
+	for _, block := range blocks {
+		head := strings.TrimLeft(block[0], " ")
+		if m := apibAttributesRe.FindStringSubmatch(head); m != nil {
+			out.attrType = m[1]
+			if membersTyp, ok := parseApibMembersType(apibUnindent(block[1:], 8)); ok && out.attrType == OBJECT {
+				out.attrNested = membersTyp
+				continue
+			}
+			if out.attrType != OBJECT {
+				typ, inline, err := parseApibInlineObject(out.attrType, apibUnindent(block[1:], 8))
+				if err != nil {
+					return nil, err
+				}
+				if inline != nil {
+					out.attrNested, out.attrInline = typ, inline
+					continue
+				}
+			}
+			attrs, err := parseApibEntries(apibUnindent(block[1:], 8))
+			if err != nil {
+				return nil, err
+			}
+			out.attrs = attrs
+		} else if head == "+ Headers" {
+			for _, line := range block[1:] {
+				if line = strings.TrimLeft(line, " "); line == "" {
+					continue
+				}
+				header, err := parseApibHeader(line)
+				if err != nil {
+					return nil, err
+				}
+				out.headers = append(out.headers, header)
+			}
+		} else if head == "+ Body" {
+			out.body = apibBody(block[1:])
+		}
+	}
+	return out, nil
+}
+
+// isApibSecurityParam checks whether given param is generated from the security requirement.
+func isApibSecurityParam(desc, secReqName string) bool {
+	if secReqName == "" {
+		return false
+	}
+	re := regexp.MustCompile(`^` + regexp.QuoteMeta(secReqName) + `(?: \(.*\))?, (apiKey|basic|oauth2)$`)
+	return re.MatchString(desc)
+}
+
+// ==========================
+// action & group & structure
+// ==========================
+
+func parseApibAction(lines []string, route string, tag string) (*Operation, error) {
+	m := apibActionRe.FindStringSubmatch(lines[0])
+	op := &Operation{method: strings.ToLower(m[2]), route: route, summary: m[1]}
+	if tag != "" {
+		op.tags = []string{tag}
+	}
+
+	leading, blocks := apibSplitBy(lines[1:], func(line string) bool { return strings.HasPrefix(line, "+ ") })
+	paragraphs := apibParagraphs(leading)
+	if len(paragraphs) > 0 {
+		if mm := apibRawRouteRe.FindStringSubmatch(paragraphs[0]); mm != nil {
+			op.route, paragraphs = mm[1], paragraphs[1:]
+		}
+	}
+	op.desc, op.additionalDoc = apibSplitDocs(paragraphs, func(p string) bool {
+		switch {
+		case p == "**Attention: This api is deprecated!**":
+			op.deprecated = true
+		case strings.HasPrefix(p, "Security requirement: "):
+			op.securities = []string{strings.TrimPrefix(p, "Security requirement: ")}
+		case apibLinkRe.MatchString(p) && !strings.Contains(p, "\n"):
+			mm := apibLinkRe.FindStringSubmatch(p)
+			op.externalDoc = parseApibExternalDoc(mm[1], mm[2])
+		default:
+			return false
+		}
+		return true
+	})
+	secReqName := ""
+	if len(op.securities) > 0 {
+		secReqName = op.securities[0]
+	}
+
+	for _, block := range blocks {
+		head := block[0]
+		if head == "+ Parameters" {
+			entries, err := parseApibEntries(apibUnindent(block[1:], 4))
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				if isApibSecurityParam(e.desc, secReqName) {
+					continue
+				}
+				in := QUERY
+				if strings.Contains(op.route, "{"+e.name+"}") {
+					in = PATH
+				}
+				op.params = append(op.params, parseApibParam(e, in))
+			}
+		} else if mm := apibRequestRe.FindStringSubmatch(head); mm != nil {
+			req, err := parseApibPayload(mm[1], block[1:])
+			if err != nil {
+				return nil, err
+			}
+			if req.mime != JSON {
+				op.consumes = []string{req.mime}
+			}
+			if req.attrType != "" {
+				if len(req.attrs) > 0 {
+					for _, a := range req.attrs {
+						op.params = append(op.params, parseApibParam(a, FORM))
+					}
+				} else {
+					typ, _ := parseApibType(req.attrType, "")
+					if req.attrNested != "" {
+						typ = req.attrNested
+					}
+					op.params = append(op.params, &Param{name: "body", in: BODY, typ: typ, required: true, inlineObject: req.attrInline})
+				}
+			}
+			for _, h := range req.headers {
+				if !isApibSecurityParam(h.desc, secReqName) {
+					op.params = append(op.params, parseApibParam(h, HEADER))
+				}
+			}
+			if req.body != "" {
+				op.reqExample = req.body
+			}
+		} else if mm := apibResponseRe.FindStringSubmatch(head); mm != nil {
+			resp, err := parseApibPayload(mm[2], block[1:])
+			if err != nil {
+				return nil, err
+			}
+			code, _ := strconv.Atoi(mm[1])
+			if resp.mime != JSON {
+				op.produces = []string{resp.mime}
+			}
+			r := &Response{code: code}
+			r.desc, r.additionalDoc = apibSplitDocs(resp.paragraphs, nil)
+			if r.desc == strconv.Itoa(code)+" "+http.StatusText(code) {
+				r.desc = "" // generated by default
+			}
+			if resp.attrType != "" {
+				r.typ, _ = parseApibType(resp.attrType, "")
+				if resp.attrNested != "" {
+					r.typ, r.inlineObject = resp.attrNested, resp.attrInline
+				}
+				if len(resp.attrs) > 0 {
+					r.typ, r.inlineObject = OBJECT, &InlineObject{properties: make([]*Property, 0, len(resp.attrs))}
+					for _, a := range resp.attrs {
+						r.inlineObject.properties = append(r.inlineObject.properties, parseApibProperty(a))
+					}
+				}
+			}
+			for _, h := range resp.headers {
+				r.headers = append(r.headers, &ResponseHeader{name: h.name, typ: h.typ, desc: h.desc, example: h.example})
+			}
+			if resp.body != "" {
+				r.examples = []*ResponseExample{{mime: resp.mime, example: resp.body}}
+			}
+			op.responses = append(op.responses, r)
+		}
+	}
+	return op, nil
+}
+
+func parseApibExternalDoc(desc, url string) *ExternalDoc {
+	if desc == url {
+		desc = ""
+	}
+	return &ExternalDoc{desc: desc, url: url}
+}
+
+func parseApibGroup(lines []string, opt *Option) ([]*Operation, error) {
+	name := apibGroupRe.FindStringSubmatch(lines[0])[1]
+	leading, resources := apibSplitBy(lines[1:], apibResourceRe.MatchString)
+	tag := &Tag{name: name}
+	tag.desc, tag.additionalDoc = apibSplitDocs(apibParagraphs(leading), func(p string) bool {
+		if m := apibLinkRe.FindStringSubmatch(p); m != nil && !strings.Contains(p, "\n") {
+			tag.externalDoc = parseApibExternalDoc(m[1], m[2])
+			return true
+		}
+		return false
+	})
+	tagName := name
+	if name == "Default" && tag.desc == "" && tag.additionalDoc == "" && tag.externalDoc == nil {
+		tagName = "" // generated for the operations without tag
+	} else {
+		opt.tags = append(opt.tags, tag)
+	}
+
+	out := make([]*Operation, 0, len(resources))
+	for _, resource := range resources {
+		m := apibResourceRe.FindStringSubmatch(resource[0])
+		summary, route := m[1], m[2]
+		if idx := strings.Index(route, "{?"); idx != -1 {
+			route = route[:idx]
+		}
+		leading, actions := apibSplitBy(resource[1:], apibActionRe.MatchString)
+		paragraphs := apibParagraphs(leading)
+		if len(paragraphs) > 0 {
+			if mm := apibRawRouteRe.FindStringSubmatch(paragraphs[0]); mm != nil {
+				route, paragraphs = mm[1], paragraphs[1:]
+			}
+		}
+
+		summaries := make([]string, 0, len(actions))
+		for _, action := range actions {
+			op, err := parseApibAction(action, route, tagName)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, op.summary)
+			out = append(out, op)
+		}
+		ro := &RoutesOption{route: m[2], additionalDoc: strings.Join(paragraphs, "\n\n")}
+		if summary != strings.Join(summaries, " | ") {
+			ro.summary = summary
+		}
+		if ro.summary != "" || ro.additionalDoc != "" {
+			opt.routesOptions = append(opt.routesOptions, ro)
+		}
+	}
+	return out, nil
+}
+
+// parseApibEnumStructure parses the enum type and members of an enum data structure into given Definition.
+func parseApibEnumStructure(def *Definition, apibTyp string, lines []string) {
+	typ, _ := parseApibType(apibTyp, "")
+	if typ == NUMBER+"#" {
+		typ = NUMBER // format of enum is not supported
+	}
+	def.enumType = typ
+	for _, line := range lines {
+		m := apibEnumMemberRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		def.enums = append(def.enums, parsePrimeValue(m[1], typ))
+		def.enumDescs = append(def.enumDescs, m[2])
+	}
+	for _, desc := range def.enumDescs {
+		if desc != "" {
+			return
+		}
+	}
+	def.enumDescs = nil // no description
+}
+
+func parseApibStructures(lines []string) ([]*Definition, error) {
+	_, structs := apibSplitBy(lines, apibStructRe.MatchString)
+	out := make([]*Definition, 0, len(structs))
+	for _, s := range structs {
+		m := apibStructRe.FindStringSubmatch(s[0])
+		name := m[1]
+		def := &Definition{name: parseSafeDefinitionName(name)}
+		if m[2] != OBJECT {
+			parseApibEnumStructure(def, m[2], s[1:])
+			out = append(out, def)
+			continue
+		}
+		lines := make([]string, 0, len(s)-1)
+		for _, line := range s[1:] {
+			if m := apibIncludeRe.FindStringSubmatch(line); m != nil {
+				def.extends = append(def.extends, parseSafeDefinitionName(m[1]))
+				continue
+			}
+			lines = append(lines, line)
+		}
+		entries, err := parseApibEntries(lines)
+		if err != nil {
+			return nil, fmt.Errorf("data structure `%s`: %v", name, err)
+		}
+		for _, e := range entries {
+			def.properties = append(def.properties, parseApibProperty(e))
+		}
+		out = append(out, def)
+	}
+	return out, nil
+}
+
+// ========
+// metadata
+// ========
+
+func parseApibSecurities(lines []string) []*Security {
+	out := make([]*Security, 0, 2)
+	for _, line := range lines {
+		if m := apibSecurityRe.FindStringSubmatch(line); m != nil {
+			out = append(out, &Security{title: m[1], typ: m[2], desc: m[3]})
+			continue
+		}
+		m := apibSecOptionRe.FindStringSubmatch(line)
+		if m == nil || len(out) == 0 {
+			continue
+		}
+		sec := out[len(out)-1]
+		switch m[1] {
+		case "name":
+			sec.name = m[2]
+		case "in":
+			sec.in = m[2]
+		case "flow":
+			sec.flow = m[2]
+		case "authUrl":
+			sec.authorizationUrl = m[2]
+		case "tokenUrl":
+			sec.tokenUrl = strings.TrimSuffix(m[2], "`")
+		case "scope":
+			parts := strings.SplitN(m[2], " - ", 2)
+			scope := &SecurityScope{scope: parts[0]}
+			if len(parts) == 2 {
+				scope.desc = parts[1]
+			}
+			sec.scopes = append(sec.scopes, scope)
+		}
+	}
+	return out
+}
+
+func parseApibList(p string) []string {
+	out := make([]string, 0, 2)
+	for _, line := range strings.Split(p, "\n") {
+		if strings.HasPrefix(line, "+ ") {
+			out = append(out, strings.TrimPrefix(line, "+ "))
+		}
+	}
+	return out
+}
+
+func parseApibDocument(lines []string) (*Document, error) {
+	out := &Document{info: &Info{}}
+	opt := &Option{}
+
+	// metadata
+	idx := 0
+	for ; idx < len(lines) && !strings.HasPrefix(lines[idx], "# "); idx++ {
+		if strings.HasPrefix(lines[idx], "HOST: ") {
+			host := strings.TrimPrefix(lines[idx], "HOST: ")
+			if slash := strings.Index(host, "/"); slash != -1 {
+				out.host, out.basePath = host[:slash], host[slash:]
+			} else {
+				out.host, out.basePath = host, "/"
+			}
+		}
+	}
+	if idx == len(lines) {
+		return nil, fmt.Errorf("api name is not found")
+	}
+	if m := apibTitleRe.FindStringSubmatch(lines[idx]); m != nil {
+		out.info.title, out.info.version = m[1], m[2]
+	} else {
+		out.info.title = strings.TrimPrefix(lines[idx], "# ")
+	}
+	lines = lines[idx+1:]
+
+	// split into overview, groups and data structures
+	var structLines []string
+	for i, line := range lines {
+		if line == "# Data Structures" {
+			lines, structLines = lines[:i], lines[i+1:]
+			break
+		}
+	}
+	overview, groups := apibSplitBy(lines, apibGroupRe.MatchString)
+
+	// overview
+	listName := ""
+	out.info.desc, opt.additionalDoc = apibSplitDocs(apibParagraphs(overview), func(p string) bool {
+		if listName != "" {
+			switch listName {
+			case "Securities defined":
+				opt.securities = parseApibSecurities(strings.Split(p, "\n"))
+			case "Supported schemes":
+				opt.schemes = parseApibList(p)
+			case "Available consumes":
+				opt.consumes = parseApibList(p)
+			case "Available produces":
+				opt.produces = parseApibList(p)
+			}
+			listName = ""
+			return true
+		}
+		switch p {
+		case "## Securities defined", "## Supported schemes", "## Available consumes", "## Available produces":
+			listName = strings.TrimPrefix(p, "## ")
+			return true
+		}
+		if m := apibTermsRe.FindStringSubmatch(p); m != nil {
+			out.info.termsOfService = m[1]
+		} else if m := apibLicenseRe.FindStringSubmatch(p); m != nil {
+			out.info.license = &License{name: m[1], url: m[2]}
+			if m[3] != "" {
+				out.info.license.name = m[3]
+			}
+		} else if m := apibWebsiteRe.FindStringSubmatch(p); m != nil {
+			out.info.contact = parseApibContact(out.info.contact, m[1])
+			out.info.contact.url = m[2]
+		} else if m := apibEmailRe.FindStringSubmatch(p); m != nil {
+			out.info.contact = parseApibContact(out.info.contact, m[1])
+			out.info.contact.email = m[2]
+		} else if m := apibLinkRe.FindStringSubmatch(p); m != nil && !strings.Contains(p, "\n") {
+			opt.externalDoc = parseApibExternalDoc(m[1], m[2])
+		} else {
+			return false
+		}
+		return true
+	})
+
+	// groups & data structures
+	for _, group := range groups {
+		ops, err := parseApibGroup(group, opt)
+		if err != nil {
+			return nil, err
+		}
+		out.operations = append(out.operations, ops...)
+	}
+	definitions, err := parseApibStructures(structLines)
+	if err != nil {
+		return nil, err
+	}
+	out.definitions = definitions
+
+	if !reflect.DeepEqual(*opt, Option{}) {
+		out.option = opt
+	}
+	return out, nil
+}
+
+func parseApibContact(contact *Contact, name string) *Contact {
+	if contact == nil {
+		contact = &Contact{}
+	}
+	if name != "the developer" {
+		contact.name = name
+	}
+	return contact
+}
@@ -0,0 +1,80 @@
+package goapidoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestLoadApib(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		giveFile  string
+		wantEqual bool
+	}{
+		{"api1", "./docs/api1.apib", true},
+		{"api2", "./docs/api2.apib", true},
+		{"api3", "./docs/api3.apib", false}, // specialized generic definitions are renamed
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := LoadApib(tc.giveFile)
+			if err != nil {
+				failNow(t, fmt.Sprintf("LoadApib (%s) error: %v", tc.name, err))
+			}
+			bs, err := doc.GenerateApib()
+			if err != nil {
+				failNow(t, fmt.Sprintf("GenerateApib (%s) error: %v", tc.name, err))
+			}
+			if _, err = doc.GenerateSwaggerJson(); err != nil {
+				failNow(t, fmt.Sprintf("GenerateSwaggerJson (%s) error: %v", tc.name, err))
+			}
+			if tc.wantEqual {
+				origin, _ := ioutil.ReadFile(tc.giveFile)
+				if string(bs) != string(origin) {
+					failNow(t, fmt.Sprintf("Regenerated apib (%s) is not equal to the original one", tc.name))
+				}
+			}
+		})
+	}
+
+	if _, err := LoadApib("./docs/not_found.apib"); err == nil {
+		failNow(t, "LoadApib should return error but no error returned for not found file")
+	}
+}
+
+func TestParseApib(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		give    string
+		wantErr bool
+	}{
+		{"empty", "", true},
+		{"without name", "FORMAT: 1A\nHOST: localhost/\n", true},
+		{"only name", "FORMAT: 1A\nHOST: localhost/\n\n# Demo (1.0)\n", false},
+		{"invalid parameter", "# Demo (1.0)\n\n# Group A\n\n## A [/]\n\n### A [GET]\n\n+ Parameters\n\n    + a string\n", true},
+		{"invalid header", "# Demo (1.0)\n\n# Group A\n\n## A [/]\n\n### A [GET]\n\n+ Request (application/json)\n\n    + Headers\n\n            Authorization\n", true},
+		{"invalid property", "# Demo (1.0)\n\n# Data Structures\n\n## A (object)\n\n+ a\n", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseApib([]byte(tc.give))
+			if (err == nil && tc.wantErr) || (err != nil && !tc.wantErr) {
+				failNow(t, fmt.Sprintf("ParseApib get an unexpected error result for %s: %v", tc.name, err))
+			}
+		})
+	}
+
+	doc, _ := ParseApib([]byte("FORMAT: 1A\nHOST: localhost/v1\n\n# Demo (1.0)\n\n# Group A\n\n## A [/{id}{?q}]\n\n### A [GET]\n\n+ Parameters\n\n    + id (number, required)\n        (format: int64)\n    + q (enum[number], optional)\n        (format: int32)\n        + Default: `1`\n        + Members\n            + `1`\n            + `2`\n\n+ Response 200 (application/json)\n\n    200 OK\n\n    + Attributes (array[_Result<_Page<UserDto>>])\n"))
+	if doc.host != "localhost" || doc.basePath != "/v1" || doc.info.title != "Demo" || doc.info.version != "1.0" {
+		failNow(t, "ParseApib get a wrong metadata")
+	}
+	op := doc.operations[0]
+	if op.route != "/{id}" || op.tags[0] != "A" || op.params[0].in != PATH || op.params[0].typ != "integer#int64" {
+		failNow(t, "ParseApib get a wrong operation or path param")
+	}
+	if op.params[1].in != QUERY || op.params[1].typ != INTEGER || op.params[1].defaul != int64(1) || len(op.params[1].enum) != 2 {
+		failNow(t, "ParseApib get a wrong query param")
+	}
+	if op.responses[0].desc != "" || op.responses[0].typ != "_Result__Page_UserDto[]" {
+		failNow(t, "ParseApib get a wrong response")
+	}
+}
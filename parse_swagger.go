@@ -0,0 +1,705 @@
+package goapidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// swagOrder represents the key orders of paths and definitions in a swagger document, which are lost when decoding to swagDocument.
+type swagOrder struct {
+	Paths       yaml.MapSlice `yaml:"paths"`
+	Definitions yaml.MapSlice `yaml:"definitions"`
+}
+
+// swagPathItem represents the non-operation fields of a path item in a swagger document, which are split from the operations when parsing.
+type swagPathItem struct {
+	Parameters []*swagParam `yaml:"parameters"`
+}
+
+// ParseSwaggerYaml parses given swagger 2.0 yaml script and returns a new Document.
+func ParseSwaggerYaml(bs []byte) (*Document, error) {
+	bs, pathItems, err := splitSwagPathItems(bs)
+	if err != nil {
+		return nil, err
+	}
+	doc := &swagDocument{}
+	if err := yaml.Unmarshal(bs, doc); err != nil {
+		return nil, err
+	}
+	order := &swagOrder{}
+	if err := yaml.Unmarshal(bs, order); err != nil {
+		return nil, err
+	}
+	if doc.Swagger != "2.0" {
+		return nil, fmt.Errorf("unsupported swagger version `%s`", doc.Swagger)
+	}
+	return parseSwagDocument(doc, order, pathItems)
+}
+
+// ParseSwaggerJson parses given swagger 2.0 json script and returns a new Document.
+func ParseSwaggerJson(bs []byte) (*Document, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	tree, err := decodeOrderedJson(decoder)
+	if err != nil {
+		return nil, err
+	}
+	yamlBs, err := yaml.Marshal(tree) // json -> yaml, keep the key orders
+	if err != nil {
+		return nil, err
+	}
+	return ParseSwaggerYaml(yamlBs)
+}
+
+// LoadSwagger loads and parses given swagger 2.0 file, json or yaml is determined by file extension.
+func LoadSwagger(path string) (*Document, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseSwaggerJson(bs)
+	case ".yaml", ".yml":
+		return ParseSwaggerYaml(bs)
+	}
+	return nil, fmt.Errorf("unsupported swagger file extension `%s`", filepath.Ext(path))
+}
+
+// splitSwagPathItems removes the non-operation fields (parameters and x-xxx) from the path items of given swagger yaml script, returns the
+// rest script which can be decoded to swagDocument, and the removed path items. Notes that path item $ref is not supported.
+func splitSwagPathItems(bs []byte) ([]byte, map[string]*swagPathItem, error) {
+	root := yaml.MapSlice{}
+	if err := yaml.Unmarshal(bs, &root); err != nil {
+		return nil, nil, err
+	}
+	found := false
+	pathItems := make(map[string]*swagPathItem)
+	for _, item := range root {
+		paths, ok := item.Value.(yaml.MapSlice)
+		if !ok || fmt.Sprintf("%v", item.Key) != "paths" {
+			continue
+		}
+		for j, pathItem := range paths {
+			fields, ok := pathItem.Value.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			methods, others := yaml.MapSlice{}, yaml.MapSlice{}
+			for _, field := range fields {
+				switch strings.ToLower(fmt.Sprintf("%v", field.Key)) {
+				case GET, PUT, POST, DELETE, OPTIONS, HEAD, PATCH:
+					methods = append(methods, field)
+				case "$ref":
+					return nil, nil, fmt.Errorf("path `%v`: path item $ref is not supported", pathItem.Key)
+				default:
+					others = append(others, field)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			found = true
+			paths[j].Value = methods
+			othersBs, _ := yaml.Marshal(others)
+			pi := &swagPathItem{}
+			if err := yaml.Unmarshal(othersBs, pi); err != nil {
+				return nil, nil, fmt.Errorf("path `%v`: %v", pathItem.Key, err)
+			}
+			pathItems[fmt.Sprintf("%v", pathItem.Key)] = pi
+		}
+	}
+	if !found {
+		return bs, pathItems, nil // unchanged
+	}
+	out, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, pathItems, nil
+}
+
+// decodeOrderedJson decodes a json value into yaml.MapSlice, []interface{} or primitive value, with the key orders kept.
+func decodeOrderedJson(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			ms := yaml.MapSlice{}
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeOrderedJson(decoder)
+				if err != nil {
+					return nil, err
+				}
+				ms = append(ms, yaml.MapItem{Key: key, Value: value})
+			}
+			_, err = decoder.Token() // }
+			return ms, err
+		case '[':
+			arr := make([]interface{}, 0)
+			for decoder.More() {
+				value, err := decodeOrderedJson(decoder)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, value)
+			}
+			_, err = decoder.Token() // ]
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected json delimiter `%s`", t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil // string, bool, nil
+	}
+}
+
+// normalizeYamlValue converts map[interface{}]interface{} decoded by yaml to map[string]interface{} recursively.
+func normalizeYamlValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			out[fmt.Sprintf("%v", k)] = normalizeYamlValue(v)
+		}
+		return out
+	case yaml.MapSlice:
+		out := make(map[string]interface{}, len(val))
+		for _, item := range val {
+			out[fmt.Sprintf("%v", item.Key)] = normalizeYamlValue(item.Value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+		for _, v := range val {
+			out = append(out, normalizeYamlValue(v))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func normalizeYamlValues(vs []interface{}) []interface{} {
+	if vs == nil {
+		return nil
+	}
+	return normalizeYamlValue(vs).([]interface{})
+}
+
+// ==========================
+// type & schema & definition
+// ==========================
+
+var swagUnsafeNameRe = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// parseSwagRef parses the definition name from given $ref, specialized generic definition names will be replaced to safe names.
+func parseSwagRef(ref string) (string, error) {
+	if !strings.HasPrefix(ref, "#/definitions/") {
+		return "", fmt.Errorf("unsupported reference `%s`", ref)
+	}
+	name := strings.TrimPrefix(ref, "#/definitions/")
+	return parseSafeDefinitionName(name), nil
+}
+
+// parseSafeDefinitionName returns a safe definition name, such as `_Result<_Page<UserDto>>` -> `_Result__Page_UserDto`.
+func parseSafeDefinitionName(name string) string {
+	if genericNameRe.MatchString(name) {
+		return name
+	}
+	return strings.TrimRight(swagUnsafeNameRe.ReplaceAllString(name, "_"), "_")
+}
+
+// parseSwagType builds the type string from given swagger type, format, items, additionalProperties and $ref.
+func parseSwagType(typ, format string, items, additional *swagItems, ref string) (string, error) {
+	if ref != "" {
+		return parseSwagRef(ref)
+	}
+	switch typ {
+	case ARRAY:
+		if items == nil {
+			return "", fmt.Errorf("array type without items is not supported")
+		}
+		item, err := parseSwagType(items.Type, items.Format, items.Items, items.AdditionalProperties, items.Ref)
+		if err != nil {
+			return "", err
+		}
+		return item + "[]", nil
+	case INTEGER, NUMBER, STRING, BOOLEAN, FILE:
+		if format == defaultFormat(typ) {
+			return typ, nil
+		}
+		return typ + "#" + format, nil // integer# means no format
+	case OBJECT, "":
+		if additional != nil {
+			value, err := parseSwagType(additional.Type, additional.Format, additional.Items, additional.AdditionalProperties, additional.Ref)
+			if err != nil {
+				return "", err
+			}
+			return MAP + "<" + STRING + ", " + value + ">", nil
+		}
+		if typ == OBJECT {
+			return OBJECT, nil // free-form object
+		}
+		return "", fmt.Errorf("inline object type is not supported")
+	}
+	return "", fmt.Errorf("unsupported type `%s`", typ)
+}
+
+func parseSwagXMLRepr(xml *swagXMLRepr) *XMLRepr {
+	if xml == nil {
+		return nil
+	}
+	return &XMLRepr{name: xml.Name, namespace: xml.Namespace, prefix: xml.Prefix, attribute: xml.Attribute, wrapped: xml.Wrapped}
+}
+
+func parseSwagExternalDoc(doc *swagExternalDoc) *ExternalDoc {
+	if doc == nil {
+		return nil
+	}
+	return &ExternalDoc{desc: doc.Description, url: doc.Url}
+}
+
+// parseSwagItemOption parses the option of array items, or map values if items is nil.
+func parseSwagItemOption(items, additional *swagItems) *ItemOption {
+	if items == nil {
+		items = additional
+	}
+	if items == nil || items.Ref != "" {
+		return nil
+	}
+	opt := &ItemOption{
+		allowEmpty:       items.AllowEmpty,
+		defaul:           normalizeYamlValue(items.Default),
+		example:          normalizeYamlValue(items.Example),
+		pattern:          items.Pattern,
+		enum:             normalizeYamlValues(items.Enum),
+		minLength:        items.MinLength,
+		maxLength:        items.MaxLength,
+		minItems:         items.MinItems,
+		maxItems:         items.MaxItems,
+		uniqueItems:      items.UniqueItems,
+		collectionFormat: items.CollectionFormat,
+		minimum:          items.Minimum,
+		maximum:          items.Maximum,
+		exclusiveMin:     items.ExclusiveMin,
+		exclusiveMax:     items.ExclusiveMax,
+		multipleOf:       items.MultipleOf,
+		itemOption:       parseSwagItemOption(items.Items, items.AdditionalProperties),
+		xmlRepr:          parseSwagXMLRepr(items.XMLRepr),
+	}
+	if reflect.DeepEqual(*opt, ItemOption{}) {
+		return nil // empty option
+	}
+	return opt
+}
+
+func parseSwagParam(p *swagParam) (*Param, error) {
+	param := &Param{name: p.Name, in: p.In, required: p.Required, desc: p.Description}
+	var err error
+	if p.In != BODY {
+		param.typ, err = parseSwagType(p.Type, p.Format, p.Items, nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("param `%s`: %v", p.Name, err)
+		}
+		param.allowEmpty = p.AllowEmpty
+		param.defaul = normalizeYamlValue(p.Default)
+		param.example = normalizeYamlValue(p.Example)
+		param.pattern = p.Pattern
+		param.enum = normalizeYamlValues(p.Enum)
+		param.minLength = p.MinLength
+		param.maxLength = p.MaxLength
+		param.minItems = p.MinItems
+		param.maxItems = p.MaxItems
+		param.uniqueItems = p.UniqueItems
+		param.collectionFormat = p.CollectionFormat
+		param.minimum = p.Minimum
+		param.maximum = p.Maximum
+		param.exclusiveMin = p.ExclusiveMin
+		param.exclusiveMax = p.ExclusiveMax
+		param.multipleOf = p.MultipleOf
+		param.xmlRepr = parseSwagXMLRepr(p.XMLRepr)
+		param.itemOption = parseSwagItemOption(p.Items, nil)
+		return param, nil
+	}
+
+	// body param
+	s := p.Schema
+	if s == nil {
+		return nil, fmt.Errorf("body param `%s` without schema is not supported", p.Name)
+	}
+	param.typ, param.inlineObject, err = parseSwagInlineObject(s.Type, s.Items, s.Properties, s.Required)
+	if err == nil && param.inlineObject == nil {
+		param.typ, err = parseSwagType(s.Type, s.Format, s.Items, s.AdditionalProperties, s.Ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("param `%s`: %v", p.Name, err)
+	}
+	param.allowEmpty = s.AllowEmpty
+	param.defaul = normalizeYamlValue(s.Default)
+	param.example = normalizeYamlValue(s.Example)
+	param.pattern = s.Pattern
+	param.enum = normalizeYamlValues(s.Enum)
+	param.minLength = s.MinLength
+	param.maxLength = s.MaxLength
+	param.minItems = s.MinItems
+	param.maxItems = s.MaxItems
+	param.uniqueItems = s.UniqueItems
+	param.collectionFormat = s.CollectionFormat
+	param.minimum = s.Minimum
+	param.maximum = s.Maximum
+	param.exclusiveMin = s.ExclusiveMin
+	param.exclusiveMax = s.ExclusiveMax
+	param.multipleOf = s.MultipleOf
+	param.xmlRepr = parseSwagXMLRepr(s.XMLRepr)
+	param.itemOption = parseSwagItemOption(s.Items, s.AdditionalProperties)
+	return param, nil
+}
+
+func parseSwagResponse(code string, r *swagResponse) (*Response, error) {
+	c, err := strconv.Atoi(code)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported response code `%s`", code)
+	}
+	resp := &Response{code: c, desc: r.Description}
+	if r.Schema != nil {
+		s := r.Schema
+		resp.typ, resp.inlineObject, err = parseSwagInlineObject(s.Type, s.Items, s.Properties, s.Required)
+		if err == nil && resp.inlineObject == nil {
+			resp.typ, err = parseSwagType(s.Type, s.Format, s.Items, s.AdditionalProperties, s.Ref)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("response `%s`: %v", code, err)
+		}
+	}
+
+	headerNames := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		h := r.Headers[name]
+		typ, err := parseSwagType(h.Type, h.Format, nil, nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("response `%s` header `%s`: %v", code, name, err)
+		}
+		resp.headers = append(resp.headers, &ResponseHeader{name: name, typ: typ, desc: h.Description, example: normalizeYamlValue(h.Example)})
+	}
+
+	mimes := make([]string, 0, len(r.Examples))
+	for mime := range r.Examples {
+		mimes = append(mimes, mime)
+	}
+	sort.Strings(mimes)
+	for _, mime := range mimes {
+		resp.examples = append(resp.examples, &ResponseExample{mime: mime, example: normalizeYamlValue(r.Examples[mime])})
+	}
+	return resp, nil
+}
+
+// parseSwagInlineObject parses the inline object from given schema, returns nil inline object if the schema is neither an object with
+// properties nor an array of it.
+func parseSwagInlineObject(typ string, items *swagItems, properties *orderedMap, required []string) (string, *InlineObject, error) {
+	if typ == ARRAY && items != nil {
+		item, inline, err := parseSwagInlineObject(items.Type, items.Items, items.Properties, items.Required)
+		if err != nil || inline == nil {
+			return "", inline, err
+		}
+		return item + "[]", inline, nil
+	}
+	if (typ != OBJECT && typ != "") || properties == nil {
+		return "", nil, nil
+	}
+	props, err := parseSwagProperties(properties, required)
+	if err != nil {
+		return "", nil, err
+	}
+	return OBJECT, &InlineObject{properties: props}, nil
+}
+
+// parseSwagProperties parses the properties of definition or inline object from given properties map and required names.
+func parseSwagProperties(properties *orderedMap, required []string) ([]*Property, error) {
+	props := make([]*Property, 0, properties.Length())
+	requiredSet := make(map[string]bool, len(required))
+	for _, r := range required {
+		requiredSet[r] = true
+	}
+	for _, propName := range properties.Keys() {
+		// decode property schema from yaml.MapSlice
+		bs, err := yaml.Marshal(properties.MustGet(propName))
+		if err != nil {
+			return nil, err
+		}
+		s := &swagSchema{}
+		if err = yaml.Unmarshal(bs, s); err != nil {
+			return nil, err
+		}
+
+		typ, inline, err := parseSwagInlineObject(s.Type, s.Items, s.Properties, s.Required)
+		if err == nil && inline == nil {
+			typ, err = parseSwagType(s.Type, s.Format, s.Items, s.AdditionalProperties, s.Ref)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("property `%s`: %v", propName, err)
+		}
+		props = append(props, &Property{
+			name:             propName,
+			typ:              typ,
+			required:         requiredSet[propName],
+			desc:             s.Description,
+			allowEmpty:       s.AllowEmpty,
+			defaul:           normalizeYamlValue(s.Default),
+			example:          normalizeYamlValue(s.Example),
+			pattern:          s.Pattern,
+			enum:             normalizeYamlValues(s.Enum),
+			minLength:        s.MinLength,
+			maxLength:        s.MaxLength,
+			minItems:         s.MinItems,
+			maxItems:         s.MaxItems,
+			uniqueItems:      s.UniqueItems,
+			collectionFormat: s.CollectionFormat,
+			minimum:          s.Minimum,
+			maximum:          s.Maximum,
+			exclusiveMin:     s.ExclusiveMin,
+			exclusiveMax:     s.ExclusiveMax,
+			multipleOf:       s.MultipleOf,
+			itemOption:       parseSwagItemOption(s.Items, s.AdditionalProperties),
+			xmlRepr:          parseSwagXMLRepr(s.XMLRepr),
+			inlineObject:     inline,
+			nullable:         s.Nullable,
+			readOnly:         s.ReadOnly,
+		})
+	}
+	return props, nil
+}
+
+func parseSwagDefinition(name string, d *swagDefinition) (*Definition, error) {
+	def := &Definition{name: parseSafeDefinitionName(name), desc: d.Description, discriminator: d.Discriminator, xmlRepr: parseSwagXMLRepr(d.XMLRepr)}
+	if len(d.Enum) > 0 && d.Type != OBJECT && d.Type != "" {
+		typ, err := parseSwagType(d.Type, d.Format, nil, nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("definition `%s`: %v", name, err)
+		}
+		def.enumType, def.enums = typ, normalizeYamlValues(d.Enum)
+		def.enumVarNames, def.enumDescs = d.EnumVarNames, d.EnumDescs
+		return def, nil
+	}
+	// inline allOf schemas are merged into the definition, only $ref schemas are kept as extends
+	properties, required := newOrderedMap(0), make([]string, 0)
+	for _, s := range d.AllOf {
+		if s.Ref == "" {
+			if s.Properties != nil {
+				for _, key := range s.Properties.Keys() {
+					properties.Set(key, s.Properties.MustGet(key))
+				}
+			}
+			required = append(required, s.Required...)
+			continue
+		}
+		ext, err := parseSwagRef(s.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("definition `%s`: %v", name, err)
+		}
+		def.extends = append(def.extends, ext)
+	}
+	if d.Properties != nil {
+		for _, key := range d.Properties.Keys() {
+			properties.Set(key, d.Properties.MustGet(key))
+		}
+	}
+	required = append(required, d.Required...)
+	if properties.Length() == 0 {
+		return def, nil
+	}
+	props, err := parseSwagProperties(properties, required)
+	if err != nil {
+		return nil, fmt.Errorf("definition `%s`: %v", name, err)
+	}
+	def.properties = props
+	return def, nil
+}
+
+// ========================
+// operations & definitions
+// ========================
+
+func parseSwagOperation(method, route string, o *swagOperation, pathItem *swagPathItem) (*Operation, error) {
+	op := &Operation{
+		method:      method,
+		route:       route,
+		summary:     o.Summary,
+		desc:        o.Description,
+		operationId: o.OperationId,
+		schemes:     o.Schemes,
+		consumes:    o.Consumes,
+		produces:    o.Produces,
+		tags:        o.Tags,
+		deprecated:  o.Deprecated,
+		externalDoc: parseSwagExternalDoc(o.ExternalDoc),
+	}
+	for _, secReq := range o.Securities {
+		names := make([]string, 0, len(secReq))
+		for name := range secReq {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			op.securities = append(op.securities, name)
+			if scopes := secReq[name]; len(scopes) > 0 {
+				if op.secsScopes == nil {
+					op.secsScopes = make(map[string][]string)
+				}
+				op.secsScopes[name] = scopes
+			}
+		}
+	}
+	for _, p := range o.Parameters {
+		param, err := parseSwagParam(p)
+		if err != nil {
+			return nil, fmt.Errorf("operation `%s %s`: %v", strings.ToUpper(method), route, err)
+		}
+		op.params = append(op.params, param)
+	}
+	if pathItem != nil {
+		for _, p := range pathItem.Parameters {
+			overridden := false
+			for _, existed := range o.Parameters {
+				if existed.Name == p.Name && existed.In == p.In {
+					overridden = true // operation-level parameters override the path-level ones
+					break
+				}
+			}
+			if overridden {
+				continue
+			}
+			param, err := parseSwagParam(p)
+			if err != nil {
+				return nil, fmt.Errorf("operation `%s %s`: %v", strings.ToUpper(method), route, err)
+			}
+			op.params = append(op.params, param)
+		}
+	}
+
+	codes := make([]string, 0, len(o.Responses))
+	for code := range o.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if code == "default" {
+			logWarning(fmt.Sprintf("Default response in operation `%s %s` is not supported, this will be ignored.", strings.ToUpper(method), route))
+			continue
+		}
+		resp, err := parseSwagResponse(code, o.Responses[code])
+		if err != nil {
+			return nil, fmt.Errorf("operation `%s %s`: %v", strings.ToUpper(method), route, err)
+		}
+		op.responses = append(op.responses, resp)
+	}
+	return op, nil
+}
+
+func parseSwagSecurity(title string, s *swagSecurity) *Security {
+	sec := &Security{title: title, typ: s.Type, desc: s.Description, in: s.In, name: s.Name, flow: s.Flow, authorizationUrl: s.AuthorizationUrl, tokenUrl: s.TokenUrl}
+	scopes := make([]string, 0, len(s.Scopes))
+	for scope := range s.Scopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		sec.scopes = append(sec.scopes, &SecurityScope{scope: scope, desc: s.Scopes[scope]})
+	}
+	return sec
+}
+
+// ========
+// document
+// ========
+
+func parseSwagDocument(doc *swagDocument, order *swagOrder, pathItems map[string]*swagPathItem) (*Document, error) {
+	out := &Document{host: doc.Host, basePath: doc.BasePath}
+
+	// info
+	if info := doc.Info; info != nil {
+		out.info = &Info{title: info.Title, desc: info.Description, version: info.Version, termsOfService: info.TermsOfService}
+		if info.License != nil {
+			out.info.license = &License{name: info.License.Name, url: info.License.Url}
+		}
+		if info.Contact != nil {
+			out.info.contact = &Contact{name: info.Contact.Name, url: info.Contact.Url, email: info.Contact.Email}
+		}
+	}
+
+	// option
+	if len(doc.Schemes) > 0 || len(doc.Consumes) > 0 || len(doc.Produces) > 0 || len(doc.Tags) > 0 || len(doc.Securities) > 0 || doc.ExternalDoc != nil {
+		opt := &Option{schemes: doc.Schemes, consumes: doc.Consumes, produces: doc.Produces, externalDoc: parseSwagExternalDoc(doc.ExternalDoc)}
+		for _, t := range doc.Tags {
+			opt.tags = append(opt.tags, &Tag{name: t.Name, desc: t.Description, externalDoc: parseSwagExternalDoc(t.ExternalDoc)})
+		}
+		titles := make([]string, 0, len(doc.Securities))
+		for title := range doc.Securities {
+			titles = append(titles, title)
+		}
+		sort.Strings(titles)
+		for _, title := range titles {
+			opt.securities = append(opt.securities, parseSwagSecurity(title, doc.Securities[title]))
+		}
+		out.option = opt
+	}
+
+	// operations, in the original order
+	for _, pathItem := range order.Paths {
+		route := fmt.Sprintf("%v", pathItem.Key)
+		methods, _ := pathItem.Value.(yaml.MapSlice)
+		for _, methodItem := range methods {
+			method := strings.ToLower(fmt.Sprintf("%v", methodItem.Key))
+			o, ok := doc.Operations[route][method]
+			if !ok || o == nil {
+				continue // parameters, $ref, x-xxx, which are split by splitSwagPathItems
+			}
+			op, err := parseSwagOperation(method, route, o, pathItems[route])
+			if err != nil {
+				return nil, err
+			}
+			out.operations = append(out.operations, op)
+		}
+	}
+
+	// definitions, in the original order
+	for _, defItem := range order.Definitions {
+		name := fmt.Sprintf("%v", defItem.Key)
+		d, ok := doc.Definitions[name]
+		if !ok || d == nil {
+			continue
+		}
+		def, err := parseSwagDefinition(name, d)
+		if err != nil {
+			return nil, err
+		}
+		out.definitions = append(out.definitions, def)
+	}
+
+	return out, nil
+}
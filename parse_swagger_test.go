@@ -0,0 +1,145 @@
+package goapidoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestLoadSwagger(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		giveFile  string
+		wantEqual bool
+	}{
+		{"api1 json", "./docs/api1.json", true},
+		{"api1 yaml", "./docs/api1.yaml", true},
+		{"api2 json", "./docs/api2.json", true},
+		{"api2 yaml", "./docs/api2.yaml", true},
+		{"api3 json", "./docs/api3.json", false}, // specialized generic definitions are renamed
+		{"api3 yaml", "./docs/api3.yaml", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := LoadSwagger(tc.giveFile)
+			if err != nil {
+				failNow(t, fmt.Sprintf("LoadSwagger (%s) error: %v", tc.name, err))
+			}
+			bs, err := doc.GenerateSwaggerJson()
+			if err != nil {
+				failNow(t, fmt.Sprintf("GenerateSwaggerJson (%s) error: %v", tc.name, err))
+			}
+			if _, err = doc.GenerateApib(); err != nil {
+				failNow(t, fmt.Sprintf("GenerateApib (%s) error: %v", tc.name, err))
+			}
+			if tc.wantEqual {
+				origin, _ := ioutil.ReadFile(tc.giveFile[:len(tc.giveFile)-len(".yaml")] + ".json")
+				if string(bs) != string(origin) {
+					failNow(t, fmt.Sprintf("Regenerated swagger (%s) is not equal to the original one", tc.name))
+				}
+			}
+		})
+	}
+
+	if _, err := LoadSwagger("./docs/api1.apib"); err == nil {
+		failNow(t, "LoadSwagger should return error but no error returned for apib file")
+	}
+	if _, err := LoadSwagger("./docs/not_found.json"); err == nil {
+		failNow(t, "LoadSwagger should return error but no error returned for not found file")
+	}
+}
+
+func TestParseSwagger(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		give    string
+		wantErr bool
+	}{
+		{"invalid json", `{"swagger": `, true},
+		{"invalid version", `{"swagger": "3.0"}`, true},
+		{"empty", `{"swagger": "2.0"}`, false},
+		{"unsupported ref", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"200": {"schema": {"$ref": "x.json"}}}}}}}`, true},
+		{"free-form object", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"200": {"schema": {"type": "object"}}}}}}}`, false},
+		{"untyped schema", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"200": {"schema": {"description": "x"}}}}}}}`, true},
+		{"array without items", `{"swagger": "2.0", "paths": {"/": {"get": {"parameters": [{"name": "a", "in": "query", "type": "array"}]}}}}`, true},
+		{"body without schema", `{"swagger": "2.0", "paths": {"/": {"get": {"parameters": [{"name": "a", "in": "body"}]}}}}`, true},
+		{"invalid code", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"2xx": {}}}}}}`, true},
+		{"default response", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"default": {}}}}}}`, false},
+		{"generic ref", `{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/_Result<_Page<UserDto>>"}}}}}}}`, false},
+		{"path item ref", `{"swagger": "2.0", "paths": {"/": {"$ref": "other.json#/paths/~1"}}}`, true},
+		{"invalid property", `{"swagger": "2.0", "definitions": {"A": {"type": "object", "properties": {"a": {"description": "x"}}}}}`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSwaggerJson([]byte(tc.give))
+			if (err == nil && tc.wantErr) || (err != nil && !tc.wantErr) {
+				failNow(t, fmt.Sprintf("ParseSwaggerJson get an unexpected error result for %s: %v", tc.name, err))
+			}
+		})
+	}
+
+	doc, _ := ParseSwaggerJson([]byte(`{"swagger": "2.0", "paths": {"/": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/_Result<_Page<UserDto>>"}}}}}}}`))
+	if doc.operations[0].responses[0].typ != "_Result__Page_UserDto" {
+		failNow(t, "ParseSwaggerJson get a wrong generic definition name")
+	}
+
+	t.Run("inline allOf", func(t *testing.T) {
+		doc, err := ParseSwaggerJson([]byte(`{"swagger": "2.0", "definitions": {"Base": {"type": "object"}, "User": {"allOf": [` +
+			`{"$ref": "#/definitions/Base"}, {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}], ` +
+			`"properties": {"name": {"type": "string"}}}}}`))
+		if err != nil {
+			failNow(t, "ParseSwaggerJson failed for inline allOf: "+err.Error())
+		}
+		var user *Definition
+		for _, def := range doc.definitions {
+			if def.name == "User" {
+				user = def
+			}
+		}
+		testMatchElements(t, user.extends, []string{"Base"}, "extends", "want")
+		if len(user.properties) != 2 || user.properties[0].name != "id" || !user.properties[0].required || user.properties[1].name != "name" {
+			failNow(t, "ParseSwaggerJson does not merge inline allOf properties")
+		}
+	})
+
+	t.Run("free-form object round trip", func(t *testing.T) {
+		doc, err := ParseSwaggerJson([]byte(`{"swagger": "2.0", "host": "localhost", "basePath": "/", "info": {"title": "test", "version": "1.0"}, ` +
+			`"paths": {"/": {"get": {"summary": "root", "responses": {"200": {"description": "OK", "schema": {"type": "object", ` +
+			`"additionalProperties": {"type": "object"}}}}}}}}`))
+		if err != nil {
+			failNow(t, "ParseSwaggerJson failed for free-form object: "+err.Error())
+		}
+		if typ := doc.operations[0].responses[0].typ; typ != "map<string, object>" {
+			failNow(t, "ParseSwaggerJson get a wrong type for free-form object: "+typ)
+		}
+		if _, err = doc.GenerateSwaggerJson(); err != nil {
+			failNow(t, "GenerateSwaggerJson failed for free-form object: "+err.Error())
+		}
+	})
+}
+
+func TestParseSwaggerPathParams(t *testing.T) {
+	doc, err := LoadSwagger("./docs/path_params.yaml")
+	if err != nil {
+		failNow(t, "LoadSwagger failed: "+err.Error())
+	}
+	if len(doc.operations) != 2 {
+		failNow(t, fmt.Sprintf("LoadSwagger get a wrong operation count: %d", len(doc.operations)))
+	}
+	for _, op := range doc.operations {
+		params := make(map[string]*Param, len(op.params))
+		for _, p := range op.params {
+			params[p.in+"."+p.name] = p
+		}
+		if len(op.params) != 2 || params["path.uid"] == nil || params["header.Authorization"] == nil {
+			failNow(t, "LoadSwagger does not merge path-level params into operation "+op.method)
+		}
+		if params["path.uid"].typ != "integer#int64" {
+			failNow(t, "LoadSwagger get a wrong path-level param type: "+params["path.uid"].typ)
+		}
+		if wantRequired := op.method == GET; params["header.Authorization"].required != wantRequired {
+			failNow(t, "Operation-level param does not override the path-level one in operation "+op.method)
+		}
+	}
+	if _, err = doc.GenerateSwaggerJson(); err != nil {
+		failNow(t, "GenerateSwaggerJson failed: "+err.Error())
+	}
+}
@@ -0,0 +1,341 @@
+package goapidoc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewDefinitionFromStruct creates a Definition from given struct value (or pointer to struct) by reflection. Property names are read from
+// the `json` tag, fields without `omitempty` are treated as required, and the constraints are read from `validate` and `binding` tags (such as
+// required, min, max, len, oneof, email, uuid), `desc` (or `description`), `example` and `default` tags are also supported. Note that nested
+// structs are only referenced by their type names, and nested anonymous structs are rendered as InlineObject, use AddDefinitionsFromTypes
+// to add them all together. DocumentError is returned if the value is not a struct.
+func NewDefinitionFromStruct(name string, v interface{}) (*Definition, error) {
+	r := newStructReflector(false)
+	typ, err := r.structType(v)
+	if err != nil {
+		return nil, err
+	}
+	return r.buildDefinition(name, typ), nil
+}
+
+// AddDefinitionsFromTypes creates Definition-s from given struct values (or pointers to struct) by reflection, and adds them and all the
+// nested struct definitions into Document. Definitions are named by the struct type names (nested anonymous structs are named by the
+// field path, such as `UserDtoAddress`), and the existed definitions will be skipped. DocumentError is returned if any value is not a
+// named struct, and no definition is added in this case.
+func (d *Document) AddDefinitionsFromTypes(values ...interface{}) error {
+	r := newStructReflector(true)
+	for _, def := range d.definitions {
+		r.existed[def.name] = true
+	}
+	for _, v := range values {
+		typ, err := r.structType(v)
+		if err != nil {
+			return err
+		}
+		name := r.typeName(typ, "")
+		if name == "" {
+			return newDocumentError("Anonymous struct type `" + typ.String() + "` is not supported")
+		}
+		r.addType(name, typ)
+	}
+	d.definitions = append(d.definitions, r.definitions...)
+	return nil
+}
+
+// AddDefinitionsFromTypes creates Definition-s from given struct values (or pointers to struct) by reflection, and adds them and all the
+// nested struct definitions into global Document.
+func AddDefinitionsFromTypes(values ...interface{}) error {
+	return _document.AddDefinitionsFromTypes(values...)
+}
+
+// structReflector represents a reflector which collects Definition-s from struct types.
+type structReflector struct {
+	names       map[reflect.Type]string
+	existed     map[string]bool
+	definitions []*Definition
+	recursive   bool // recurse into nested structs or not
+}
+
+func newStructReflector(recursive bool) *structReflector {
+	return &structReflector{names: make(map[reflect.Type]string), existed: make(map[string]bool), recursive: recursive}
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte{})
+
+	unsafeTypeNameRe = regexp.MustCompile(`[^0-9A-Za-z_]+`)
+)
+
+// structType returns the struct type of given value, returns DocumentError when the value is not a struct or a pointer to struct.
+func (r *structReflector) structType(v interface{}) (reflect.Type, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, newDocumentError(fmt.Sprintf("Type `%T` is not a struct", v))
+	}
+	return typ, nil
+}
+
+// typeName returns the definition name for given struct type, anonymous struct is named by given fallback name.
+func (r *structReflector) typeName(typ reflect.Type, fallback string) string {
+	if name, ok := r.names[typ]; ok {
+		return name
+	}
+	name := typ.Name()
+	if name == "" {
+		name = fallback
+	}
+	return strings.Trim(unsafeTypeNameRe.ReplaceAllString(name, "_"), "_") // generic type name: Page[main.User]
+}
+
+// addType builds and collects the Definition for given struct type, and recurses into nested structs.
+func (r *structReflector) addType(name string, typ reflect.Type) {
+	if _, ok := r.names[typ]; ok {
+		return
+	}
+	r.names[typ] = name
+	if r.existed[name] {
+		return
+	}
+	r.existed[name] = true
+	def := r.buildDefinition(name, typ)
+	r.definitions = append(r.definitions, def)
+}
+
+// buildDefinition builds a Definition with given name and struct type.
+func (r *structReflector) buildDefinition(name string, typ reflect.Type) *Definition {
+	r.names[typ] = name
+	return &Definition{name: name, properties: r.buildProperties(name, typ)}
+}
+
+// buildProperties builds the properties of given struct type, embedded struct fields will be flattened.
+func (r *structReflector) buildProperties(name string, typ reflect.Type) []*Property {
+	out := make([]*Property, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, omitempty := tag, false
+		if idx := strings.Index(tag, ","); idx != -1 {
+			tagName, omitempty = tag[:idx], strings.Contains(tag[idx:], ",omitempty")
+		}
+
+		fieldTyp := field.Type
+		for fieldTyp.Kind() == reflect.Ptr {
+			fieldTyp = fieldTyp.Elem()
+		}
+		if field.Anonymous && tagName == "" && fieldTyp.Kind() == reflect.Struct {
+			out = append(out, r.buildProperties(name, fieldTyp)...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+
+		propTyp, inline, ok := r.buildType(field.Type, name+field.Name)
+		if !ok {
+			logWarning(fmt.Sprintf("Field `%s.%s` in type `%s` is not supported, this will be ignored.", typ.Name(), field.Name, field.Type))
+			continue
+		}
+		prop := &Property{name: tagName, typ: propTyp, required: !omitempty, inlineObject: inline}
+		applyStructTags(prop, field)
+		out = append(out, prop)
+	}
+	return out
+}
+
+var (
+	// validateFormats represents the mapping from validator's baked-in tags to formats.
+	validateFormats = map[string]string{
+		"email":    "email",
+		"uuid":     "uuid",
+		"uuid4":    "uuid",
+		"url":      "uri",
+		"uri":      "uri",
+		"ipv4":     "ipv4",
+		"ipv6":     "ipv6",
+		"hostname": "hostname",
+		"datetime": DATETIME,
+	}
+
+	oneofValueRe = regexp.MustCompile(`'[^']*'|\S+`)
+)
+
+// applyStructTags applies `desc`, `description`, `example`, `default`, `validate` and `binding` tags of given field to Property.
+func applyStructTags(p *Property, field reflect.StructField) {
+	p.desc = field.Tag.Get("desc")
+	if p.desc == "" {
+		p.desc = field.Tag.Get("description")
+	}
+	if example, ok := field.Tag.Lookup("example"); ok {
+		p.example = parsePrimeValue(example, p.typ)
+	}
+	if defaul, ok := field.Tag.Lookup("default"); ok {
+		p.defaul = parsePrimeValue(defaul, p.typ)
+	}
+
+	rules := field.Tag.Get("validate")
+	if binding := field.Tag.Get("binding"); binding != "" {
+		if rules != "" {
+			rules += ","
+		}
+		rules += binding
+	}
+	if rules == "" {
+		return
+	}
+
+	fieldTyp := field.Type
+	for fieldTyp.Kind() == reflect.Ptr {
+		fieldTyp = fieldTyp.Elem()
+	}
+	kind := fieldTyp.Kind()
+	isString := kind == reflect.String
+	isArray := kind == reflect.Slice || kind == reflect.Array
+	isMap := kind == reflect.Map
+	p.required = false // determined by validation rules
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "dive" {
+			break // the following rules are for items
+		}
+		if strings.Contains(rule, "|") {
+			continue // or-rules are not supported
+		}
+		key, value := rule, ""
+		if idx := strings.Index(rule, "="); idx != -1 {
+			key, value = rule[:idx], rule[idx+1:]
+		}
+		if key == "required" {
+			p.required = true
+			continue
+		}
+		if key == "oneof" {
+			p.enum = p.enum[:0]
+			for _, v := range oneofValueRe.FindAllString(value, -1) {
+				p.enum = append(p.enum, parsePrimeValue(strings.Trim(v, "'"), p.typ))
+			}
+			continue
+		}
+		if format, ok := validateFormats[key]; ok && isString {
+			p.typ = STRING + "#" + format
+			continue
+		}
+
+		if isMap {
+			continue // size rules of map mean minProperties and maxProperties, which are not supported
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		i := int(f)
+		switch {
+		case isString || isArray:
+			minLen, maxLen := &p.minLength, &p.maxLength
+			if isArray {
+				minLen, maxLen = &p.minItems, &p.maxItems
+			}
+			switch key {
+			case "len":
+				*minLen, *maxLen = &i, &i
+			case "min", "gte":
+				*minLen = &i
+			case "max", "lte":
+				*maxLen = &i
+			case "gt":
+				i++
+				*minLen = &i
+			case "lt":
+				i--
+				*maxLen = &i
+			}
+		default:
+			switch key {
+			case "len", "eq":
+				p.minimum, p.maximum = &f, &f
+			case "min", "gte":
+				p.minimum, p.exclusiveMin = &f, false
+			case "max", "lte":
+				p.maximum, p.exclusiveMax = &f, false
+			case "gt":
+				p.minimum, p.exclusiveMin = &f, true
+			case "lt":
+				p.maximum, p.exclusiveMax = &f, true
+			}
+		}
+	}
+}
+
+// buildType returns the type string of given reflect.Type, which can be parsed by parseApiType, and the InlineObject of anonymous struct
+// when the reflector is not recursive.
+func (r *structReflector) buildType(typ reflect.Type, fallback string) (string, *InlineObject, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ {
+	case timeType:
+		return STRING + "#" + DATETIME, nil, true
+	case bytesType:
+		return STRING + "#" + BYTE, nil, true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return BOOLEAN, nil, true
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return INTEGER, nil, true
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return INTEGER + "#" + INT64, nil, true
+	case reflect.Float32:
+		return NUMBER + "#" + FLOAT, nil, true
+	case reflect.Float64:
+		return NUMBER, nil, true
+	case reflect.String:
+		return STRING, nil, true
+	case reflect.Interface:
+		return OBJECT, nil, true // any value, rendered as free-form object
+	case reflect.Slice, reflect.Array:
+		item, inline, ok := r.buildType(typ.Elem(), fallback+"Item")
+		if !ok {
+			return "", nil, false
+		}
+		return item + "[]", inline, true
+	case reflect.Map:
+		switch typ.Key().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Array, reflect.Interface:
+			return "", nil, false // only primitive key
+		}
+		key, _, ok := r.buildType(typ.Key(), "")
+		if !ok {
+			return "", nil, false
+		}
+		value, _, ok := r.buildType(typ.Elem(), fallback+"Value")
+		if !ok {
+			return "", nil, false
+		}
+		return MAP + "<" + key + ", " + value + ">", nil, true // inline object of map value is dropped, and rendered as free-form object
+	case reflect.Struct:
+		if typ.Name() == "" && !r.recursive {
+			return OBJECT, NewInlineObject(r.buildProperties(fallback, typ)...), true
+		}
+		name := r.typeName(typ, fallback)
+		if r.recursive {
+			r.addType(name, typ)
+		}
+		return name, nil, true
+	}
+	return "", nil, false // chan, func...
+}
@@ -0,0 +1,150 @@
+package goapidoc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type reflectBaseDto struct {
+	Id        uint64    `json:"id"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+type reflectUserDto struct {
+	reflectBaseDto
+	Name     string             `json:"name" desc:"user name"`
+	Score    float32            `json:"score,omitempty"`
+	Avatar   []byte             `json:"avatar,omitempty"`
+	Tags     []string           `json:"tags"`
+	Profile  *reflectProfileDto `json:"profile"`
+	Friends  []*reflectUserDto  `json:"friends,omitempty"`
+	Extra    struct{ Age int8 } `json:"extra"`
+	Secret   string             `json:"-"`
+	NoTag    bool
+	Settings map[string]string `json:"settings"`
+	Scores   map[int][]float32 `json:"scores,omitempty"`
+	Anything interface{}       `json:"anything"`
+	internal int
+}
+
+type reflectProfileDto struct {
+	Bio string `json:"bio"`
+}
+
+func TestNewDefinitionFromStruct(t *testing.T) {
+	DisableWarningLogger()
+	defer EnableWarningLogger()
+
+	_, err := NewDefinitionFromStruct("x", 0)
+	testError(t, true, err, "NewDefinitionFromStruct")
+	_, err = NewDefinitionFromStruct("x", nil)
+	testError(t, true, err, "NewDefinitionFromStruct")
+	_, err = NewDefinitionFromStruct("x", &reflectProfileDto{})
+	testError(t, false, err, "NewDefinitionFromStruct")
+
+	def, _ := NewDefinitionFromStruct("User", reflectUserDto{})
+	for i, tc := range []struct {
+		giveName     string
+		giveType     string
+		giveRequired bool
+		giveDesc     string
+	}{
+		{"id", "integer#int64", true, ""},
+		{"created_at", "string#date-time", false, ""},
+		{"name", "string", true, "user name"},
+		{"score", "number#float", false, ""},
+		{"avatar", "string#byte", false, ""},
+		{"tags", "string[]", true, ""},
+		{"profile", "reflectProfileDto", true, ""},
+		{"friends", "User[]", false, ""},
+		{"extra", "object", true, ""},
+		{"NoTag", "boolean", true, ""},
+		{"settings", "map<string, string>", true, ""},
+		{"scores", "map<integer#int64, number#float[]>", false, ""},
+		{"anything", "object", true, ""},
+	} {
+		if i >= len(def.properties) {
+			failNow(t, fmt.Sprintf("Property `%s` is not found", tc.giveName))
+		}
+		p := def.properties[i]
+		if p.name != tc.giveName || p.typ != tc.giveType || p.required != tc.giveRequired || p.desc != tc.giveDesc {
+			failNow(t, fmt.Sprintf("Property `%s` is not expected: %s, %s, %v, %s", tc.giveName, p.name, p.typ, p.required, p.desc))
+		}
+	}
+	if len(def.properties) != 13 {
+		failNow(t, "Properties of definition have unexpected length")
+	}
+	if inline := def.properties[8].inlineObject; inline == nil || len(inline.properties) != 1 || inline.properties[0].name != "Age" {
+		failNow(t, "Anonymous struct is not rendered as inline object")
+	}
+	doc := NewDocument("localhost", "/", NewInfo("test", "", "1.0")).
+		Definitions(def, NewDefinition("reflectProfileDto", "")).
+		Operations(NewGetOperation("/user", "get user").Responses(NewResponse(200, "User")))
+	if _, err = doc.GenerateSwaggerJson(); err != nil {
+		failNow(t, "GenerateSwaggerJson failed for reflected definition: "+err.Error())
+	}
+}
+
+func TestAddDefinitionsFromTypes(t *testing.T) {
+	DisableWarningLogger()
+	defer EnableWarningLogger()
+
+	testError(t, true, NewDocument("", "", nil).AddDefinitionsFromTypes(struct{}{}), "AddDefinitionsFromTypes")
+	testError(t, true, NewDocument("", "", nil).AddDefinitionsFromTypes(reflectProfileDto{}, 0), "AddDefinitionsFromTypes")
+
+	doc := NewDocument("", "", nil).AddDefinitions(NewDefinition("reflectProfileDto", "existed"))
+	testError(t, false, doc.AddDefinitionsFromTypes(&reflectUserDto{}, reflectProfileDto{}, reflectUserDto{}), "AddDefinitionsFromTypes")
+	names := make([]string, 0, len(doc.definitions))
+	for _, def := range doc.definitions {
+		names = append(names, def.name)
+	}
+	testMatchElements(t, names, []string{"reflectProfileDto", "reflectUserDto", "reflectUserDtoExtra"}, "names", "expected names")
+	if doc.definitions[0].desc != "existed" {
+		failNow(t, "Existed definition is overwritten")
+	}
+}
+
+type reflectTagDto struct {
+	Name   string            `json:"name,omitempty" binding:"required,min=1,max=20" description:"the name"`
+	Email  *string           `json:"email" validate:"omitempty,email"`
+	Status string            `json:"status" validate:"required,oneof=active 'not active'" default:"active"`
+	Age    int32             `json:"age" validate:"gte=0,lt=150" example:"18"`
+	Code   string            `json:"code" validate:"len=6,uuid|email"`
+	Ids    []uint64          `json:"ids" binding:"gt=0,max=10,dive,min=1"`
+	Score  float64           `json:"score" binding:"gt=0.5" example:"1.5"`
+	Attrs  map[string]string `json:"attrs" validate:"required,min=1,max=5"`
+}
+
+func TestApplyStructTags(t *testing.T) {
+	def, _ := NewDefinitionFromStruct("Tag", reflectTagDto{})
+	props := make(map[string]*Property, len(def.properties))
+	for _, p := range def.properties {
+		props[p.name] = p
+	}
+
+	if p := props["name"]; !p.required || *p.minLength != 1 || *p.maxLength != 20 || p.desc != "the name" {
+		failNow(t, "Property `name` is not expected")
+	}
+	if p := props["email"]; p.required || p.typ != "string#email" {
+		failNow(t, "Property `email` is not expected")
+	}
+	if p := props["status"]; !p.required || len(p.enum) != 2 || p.enum[1] != "not active" || p.defaul != "active" {
+		failNow(t, "Property `status` is not expected")
+	}
+	if p := props["age"]; p.required || *p.minimum != 0 || p.exclusiveMin || *p.maximum != 150 || !p.exclusiveMax || p.example != int64(18) {
+		failNow(t, "Property `age` is not expected")
+	}
+	if p := props["code"]; p.typ != "string" || *p.minLength != 6 || *p.maxLength != 6 {
+		failNow(t, "Property `code` is not expected")
+	}
+	if p := props["ids"]; *p.minItems != 1 || *p.maxItems != 10 || p.minimum != nil {
+		failNow(t, "Property `ids` is not expected")
+	}
+	if p := props["score"]; *p.minimum != 0.5 || !p.exclusiveMin || p.example != 1.5 {
+		failNow(t, "Property `score` is not expected")
+	}
+	if p := props["attrs"]; !p.required || p.minItems != nil || p.maxItems != nil || p.minimum != nil || p.maximum != nil {
+		failNow(t, "Property `attrs` is not expected")
+	}
+}
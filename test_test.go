@@ -29,6 +29,14 @@ func testPanic(t *testing.T, want bool, fn func(), fnName string) {
 	}
 }
 
+func testError(t *testing.T, want bool, err error, fnName string) {
+	if err != nil && !want {
+		failNow(t, fmt.Sprintf("Test case for '%s' want no error but got '%v'", fnName, err))
+	} else if err == nil && want {
+		failNow(t, fmt.Sprintf("Test case for '%s' want error but no error returned", fnName))
+	}
+}
+
 func testMatchElements(t *testing.T, s1, s2 []string, s1Name, s2Name string) {
 	if len(s1) != len(s2) {
 		failNow(t, fmt.Sprintf("Two slice ('%s' and '%s')'s lengths is not same", s1Name, s2Name))
@@ -0,0 +1,787 @@
+package goapidoc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationIssue represents a problem found when validating Document.
+type ValidationIssue struct {
+	Location string // json-pointer-like location, such as operations[GET /user/{id}].params[id]
+	Severity string // error or warning
+	Message  string // issue message
+
+	err error // DocumentError with the location attached
+}
+
+// String returns the formatted issue with severity and location.
+func (v *ValidationIssue) String() string {
+	if v.Location == "" {
+		return fmt.Sprintf("[%s] %s", v.Severity, v.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", v.Severity, v.Location, v.Message)
+}
+
+// Validate checks the whole Document, and returns all the issues found at once, including the structure checks, type checks and warnings.
+func (d *Document) Validate() []*ValidationIssue {
+	c := &documentChecker{}
+	c.checkDocument(d)
+	c.checkTypes(d)
+	c.checkGenerics(d)
+	c.checkFormats(d)
+	return c.issues
+}
+
+// ValidateDocument checks the whole global Document, and returns all the issues found at once.
+func ValidateDocument() []*ValidationIssue {
+	return _document.Validate()
+}
+
+// checkDocument checks the structure of given Document, and returns the first error as DocumentError, notes that the warnings, such as the
+// undeclared tags and the formats which are not registered, are logged.
+func checkDocument(doc *Document) error {
+	c := &documentChecker{}
+	c.checkDocument(doc)
+	for _, issue := range c.issues {
+		if issue.Severity == SEVERITY_ERROR {
+			return issue.err
+		}
+	}
+	c.checkFormats(doc)
+	for _, issue := range c.issues {
+		logWarning(issue.Location + ": " + issue.Message)
+	}
+	return nil
+}
+
+// documentChecker represents a checker which collects all the ValidationIssue-s of Document.
+type documentChecker struct {
+	issues []*ValidationIssue
+}
+
+func (c *documentChecker) report(severity, location string, err error) {
+	msg := err.Error()
+	if e, ok := err.(*DocumentError); ok {
+		msg = e.Message
+	}
+	c.issues = append(c.issues, &ValidationIssue{Location: location, Severity: severity, Message: msg, err: err})
+}
+
+func (c *documentChecker) error(location string, err error) {
+	c.report(SEVERITY_ERROR, location, err)
+}
+
+func (c *documentChecker) warning(location string, err error) {
+	c.report(SEVERITY_WARNING, location, err)
+}
+
+func operationLocation(op *Operation) string {
+	return fmt.Sprintf("operations[%s %s]", strings.ToUpper(op.method), op.route)
+}
+
+func definitionLocation(def *Definition) string {
+	return fmt.Sprintf("definitions[%s]", def.name)
+}
+
+// ================
+// structure checks
+// ================
+
+func (c *documentChecker) checkDocument(doc *Document) {
+	if doc.host == "" {
+		c.error("host", newDocumentError("Host is required"))
+	}
+	if !strings.HasPrefix(doc.basePath, "/") {
+		c.error("basePath", newDocumentError("BasePath must begin with a slash"))
+	}
+	if doc.info == nil {
+		c.error("info", newDocumentError("Info is required"))
+	} else {
+		if doc.info.title == "" {
+			c.error("info.title", newDocumentError("Info title is required"))
+		}
+		if doc.info.version == "" {
+			c.error("info.version", newDocumentError("Info version is required"))
+		}
+		if doc.info.license != nil && doc.info.license.name == "" {
+			c.error("info.license.name", newDocumentError("License name is required"))
+		}
+	}
+
+	if doc.option != nil {
+		c.checkOption(doc.option)
+	}
+
+	if len(doc.operations) == 0 {
+		c.error("operations", newDocumentError("Empty operations is not allowed"))
+	}
+	opt := doc.option
+	if opt == nil {
+		opt = NewOption()
+	}
+	tags := make(map[string]bool, len(opt.tags))
+	for _, t := range opt.tags {
+		tags[t.name] = true
+	}
+	securities := make(map[string]*Security, len(opt.securities))
+	for _, s := range opt.securities {
+		securities[s.title] = s
+	}
+	routes := make(map[string]*Operation, len(doc.operations))
+	operationIds := make(map[string]*Operation, len(doc.operations))
+	for _, op := range doc.operations {
+		c.checkOperation(op, opt.globalParams)
+		c.checkOperationRefs(op, tags, securities)
+		key := strings.ToUpper(op.method) + " " + routeParamRe.ReplaceAllString(op.route, "{}")
+		if existed, ok := routes[key]; ok {
+			c.error(operationLocation(op), errorInOperation(newDocumentError("Operation conflicts with `"+strings.ToUpper(existed.method)+" "+existed.route+"`"), op))
+		} else {
+			routes[key] = op
+		}
+		operationId := operationIdOf(op)
+		if existed, ok := operationIds[operationId]; ok {
+			c.error(operationLocation(op)+".operationId", errorInOperation(newDocumentError("OperationId `"+operationId+"` is duplicate with `"+strings.ToUpper(existed.method)+" "+existed.route+"`"), op))
+		} else {
+			operationIds[operationId] = op
+		}
+	}
+
+	for _, def := range doc.definitions {
+		c.checkDefinition(def)
+	}
+	c.checkDiscriminators(doc.definitions)
+}
+
+func (c *documentChecker) checkOption(opt *Option) {
+	for _, t := range opt.tags {
+		loc := fmt.Sprintf("option.tags[%s]", t.name)
+		if t.name == "" {
+			c.error(loc, newDocumentError("Tag name is required"))
+		}
+		if t.externalDoc != nil && t.externalDoc.url == "" {
+			c.error(loc+".externalDoc.url", errorInField(newDocumentError("Tag external documentation url is required"), t.name))
+		}
+	}
+
+	for _, s := range opt.securities {
+		loc := fmt.Sprintf("option.securities[%s]", s.title)
+		if s.title == "" {
+			c.error(loc, newDocumentError("Security title is required"))
+		}
+		if s.typ == APIKEY {
+			if s.name == "" {
+				c.error(loc+".name", errorInField(newDocumentError("Security name is required"), s.title))
+			}
+			if s.in == "" {
+				c.error(loc+".in", errorInField(newDocumentError("Security in-location is required"), s.title))
+			}
+		} else if s.typ == BASIC {
+			// pass
+		} else if s.typ == OAUTH2 {
+			if s.flow == "" {
+				c.error(loc+".flow", errorInField(newDocumentError("Security flow is required"), s.title))
+			}
+			if (s.flow == IMPLICIT_FLOW || s.flow == ACCESSCODE_FLOW) && s.authorizationUrl == "" {
+				c.error(loc+".authorizationUrl", errorInField(newDocumentError("Security authorizationUrl is required"), s.title))
+			}
+			if (s.flow == PASSWORD_FLOW || s.flow == APPLICATION_FLOW || s.flow == ACCESSCODE_FLOW) && s.tokenUrl == "" {
+				c.error(loc+".tokenUrl", errorInField(newDocumentError("Security tokenUrl is required"), s.title))
+			}
+			if len(s.scopes) == 0 {
+				c.error(loc+".scopes", errorInField(newDocumentError("Empty security scopes is not allowed"), s.title))
+			}
+			for _, sc := range s.scopes {
+				if sc.scope == "" {
+					c.error(loc+".scopes[]", errorInField(newDocumentError("Security scope name is required"), s.title))
+				}
+			}
+		} else {
+			c.error(loc+".type", errorInField(newDocumentError("Security type `"+s.typ+"` is not supported"), s.title))
+		}
+	}
+
+	if opt.externalDoc != nil && opt.externalDoc.url == "" {
+		c.error("option.externalDoc.url", newDocumentError("Document option external documentation url is required"))
+	}
+	for _, ro := range opt.routesOptions {
+		if !strings.HasPrefix(ro.route, "/") {
+			c.error(fmt.Sprintf("option.routesOptions[%s]", ro.route), errorInField(newDocumentError("Routes options route path must begin with a slash"), ro.route))
+		}
+	}
+}
+
+var (
+	routeParamRe = regexp.MustCompile(`{([^{}/]*)}`)
+)
+
+func (c *documentChecker) checkOperation(op *Operation, globalParams []*Param) {
+	loc := operationLocation(op)
+	opError := func(subLoc string, err error) {
+		c.error(loc+subLoc, errorInOperation(err, op))
+	}
+
+	if op.method == "" {
+		opError(".method", newDocumentError("Operation method is required"))
+	}
+	if !strings.HasPrefix(op.route, "/") {
+		opError(".route", newDocumentError("Operation route path must begin with a slash"))
+	}
+	if op.summary == "" {
+		opError(".summary", newDocumentError("Operation summary is required"))
+	}
+
+	for _, p := range op.params {
+		paramLoc := fmt.Sprintf(".params[%s]", p.name)
+		if p.name == "" {
+			opError(paramLoc, newDocumentError("Request param name is required"))
+		}
+		if p.in == "" {
+			opError(paramLoc+".in", errorInField(newDocumentError("Request param in-location is required"), p.name))
+		}
+		if p.in == PATH && (!p.required || p.allowEmpty) {
+			opError(paramLoc+".required", errorInField(newDocumentError("Path param's must be non-optional and non-empty"), p.name))
+		}
+		if p.typ == "" {
+			opError(paramLoc+".type", errorInField(newDocumentError("Request param type is required"), p.name))
+		}
+		if p.inlineObject != nil {
+			p := p
+			if p.in != BODY {
+				opError(paramLoc, errorInField(newDocumentError("Inline object is only supported in body param"), p.name))
+			}
+			c.checkProperties(loc+paramLoc, "Inline object", p.inlineObject.properties, func(err error) error {
+				return errorInOperation(errorInField(err, p.name), op)
+			})
+		}
+	}
+	c.checkRouteParams(op, globalParams)
+
+	if len(op.responses) == 0 {
+		opError(".responses", newDocumentError("Empty operation response is not allowed"))
+	}
+	for _, r := range op.responses {
+		code := strconv.Itoa(r.code)
+		respLoc := fmt.Sprintf(".responses[%s]", code)
+		if r.code == 0 {
+			opError(respLoc, newDocumentError("Response code is required"))
+		}
+		if r.inlineObject != nil {
+			c.checkProperties(loc+respLoc, "Inline object", r.inlineObject.properties, func(err error) error {
+				return errorInOperation(errorInField(err, code), op)
+			})
+		}
+		for _, h := range r.headers {
+			headerLoc := fmt.Sprintf("%s.headers[%s]", respLoc, h.name)
+			if h.name == "" {
+				opError(headerLoc, errorInField(newDocumentError("Response header field name is required"), code))
+			}
+			if h.typ == "" {
+				opError(headerLoc+".type", errorInField(newDocumentError("Response header type is required"), h.name))
+			}
+		}
+		for _, e := range r.examples {
+			if e.mime == "" {
+				opError(respLoc+".examples[]", errorInField(newDocumentError("Response example mime is required"), code))
+			}
+		}
+	}
+
+	if op.externalDoc != nil && op.externalDoc.url == "" {
+		opError(".externalDoc.url", newDocumentError("Operation external documentation url is required"))
+	}
+}
+
+// checkRouteParams checks the consistency between the placeholders in operation route and path params (including the global ones, which
+// are only used by the routes with matching placeholders, see operationParams).
+func (c *documentChecker) checkRouteParams(op *Operation, globalParams []*Param) {
+	loc := operationLocation(op)
+	pathParams := make(map[string]bool, len(op.params))
+	for _, params := range [][]*Param{op.params, globalParams} {
+		for _, p := range params {
+			if p.in == PATH {
+				pathParams[p.name] = true
+			}
+		}
+	}
+
+	placeholders := make(map[string]bool, len(pathParams))
+	for _, matches := range routeParamRe.FindAllStringSubmatch(op.route, -1) {
+		name := matches[1]
+		if name == "" {
+			c.error(loc+".route", errorInOperation(newDocumentError("Route placeholder name is required"), op))
+			continue
+		}
+		if placeholders[name] {
+			c.error(loc+".route", errorInOperation(errorInField(newDocumentError("Route placeholder `{"+name+"}` is duplicate"), name), op))
+			continue
+		}
+		placeholders[name] = true
+		if !pathParams[name] {
+			c.error(loc+".route", errorInOperation(errorInField(newDocumentError("Route placeholder `{"+name+"}` has no matching path param"), name), op))
+		}
+	}
+	for _, p := range op.params {
+		if p.in == PATH && p.name != "" && !placeholders[p.name] {
+			c.error(fmt.Sprintf("%s.params[%s]", loc, p.name), errorInOperation(errorInField(newDocumentError("Path param is not found in route"), p.name), op))
+		}
+	}
+}
+
+// checkOperationRefs checks the tags, securities and security scopes referenced by operation are declared in Option, the declared tags and
+// securities are given as maps.
+func (c *documentChecker) checkOperationRefs(op *Operation, tags map[string]bool, securities map[string]*Security) {
+	loc := operationLocation(op)
+	for _, tag := range op.tags {
+		if !tags[tag] {
+			c.warning(fmt.Sprintf("%s.tags[%s]", loc, tag), errorInOperation(errorInField(newDocumentError("Tag `"+tag+"` is not declared in option"), tag), op))
+		}
+	}
+
+	for _, title := range op.securities {
+		if _, ok := securities[title]; !ok {
+			c.error(fmt.Sprintf("%s.securities[%s]", loc, title), errorInOperation(errorInField(newDocumentError("Security `"+title+"` is not declared in option"), title), op))
+		}
+	}
+	titles := make([]string, 0, len(op.secsScopes))
+	for title := range op.secsScopes {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+	for _, title := range titles {
+		scopesLoc := fmt.Sprintf("%s.securityScopes[%s]", loc, title)
+		s, ok := securities[title]
+		if !ok {
+			c.error(scopesLoc, errorInOperation(errorInField(newDocumentError("Security `"+title+"` is not declared in option"), title), op))
+			continue
+		}
+		scopes := make(map[string]bool, len(s.scopes))
+		for _, sc := range s.scopes {
+			scopes[sc.scope] = true
+		}
+		for _, scope := range op.secsScopes[title] {
+			if !scopes[scope] {
+				c.error(scopesLoc, errorInOperation(errorInField(newDocumentError("Security scope `"+scope+"` is not declared in security"), title), op))
+			}
+		}
+	}
+}
+
+func (c *documentChecker) checkDefinition(def *Definition) {
+	loc := definitionLocation(def)
+	if def.name == "" {
+		c.error(loc, newDocumentError("Definition name is required"))
+	}
+	c.checkProperties(loc, "Definition", def.properties, func(err error) error {
+		return errorInDefinition(err, def.name)
+	})
+	for _, ext := range def.extends {
+		if ext == "" {
+			c.error(loc+".extends", errorInDefinition(newDocumentError("Extended definition type is required"), def.name))
+		}
+	}
+	if def.enumType != "" || len(def.enums) > 0 {
+		c.checkEnumDefinition(def)
+	}
+}
+
+func (c *documentChecker) checkEnumDefinition(def *Definition) {
+	loc := definitionLocation(def)
+	if def.enumType == "" {
+		c.error(loc+".enumType", errorInDefinition(newDocumentError("Enum definition type is required"), def.name))
+	} else if at, err := parseApiType(def.enumType); err != nil || at.kind != apiPrimeKind || at.prime.typ == FILE || at.prime.typ == OBJECT {
+		c.error(loc+".enumType", errorInDefinition(newDocumentError("Enum type `"+def.enumType+"` must be a primitive type"), def.name))
+	}
+	if len(def.enums) == 0 {
+		c.error(loc+".enum", errorInDefinition(newDocumentError("Empty enum values is not allowed"), def.name))
+	}
+	if len(def.enumVarNames) > 0 && len(def.enumVarNames) != len(def.enums) {
+		c.error(loc+".enumVarNames", errorInDefinition(newDocumentError("Enum variable names length is not matched with enum values"), def.name))
+	}
+	if len(def.enumDescs) > 0 && len(def.enumDescs) != len(def.enums) {
+		c.error(loc+".enumDescs", errorInDefinition(newDocumentError("Enum descriptions length is not matched with enum values"), def.name))
+	}
+	if len(def.properties) > 0 || len(def.generics) > 0 || len(def.extends) > 0 || def.discriminator != "" {
+		c.error(loc, errorInDefinition(newDocumentError("Enum definition cannot have properties, generics, extends or discriminator"), def.name))
+	}
+}
+
+// checkProperties checks the properties of Definition or InlineObject, including the properties of nested inline objects.
+func (c *documentChecker) checkProperties(loc, owner string, properties []*Property, wrapFn func(err error) error) {
+	for _, p := range properties {
+		p := p
+		propLoc := fmt.Sprintf("%s.properties[%s]", loc, p.name)
+		if p.name == "" {
+			c.error(propLoc, wrapFn(newDocumentError(owner+" property name is required")))
+		}
+		if p.typ == "" {
+			c.error(propLoc+".type", wrapFn(errorInField(newDocumentError(owner+" property type is required"), p.name)))
+		}
+		if p.readOnly && p.writeOnly {
+			c.error(propLoc, wrapFn(errorInField(newDocumentError(owner+" property cannot be both readOnly and writeOnly"), p.name)))
+		}
+		if p.inlineObject != nil {
+			c.checkProperties(propLoc, "Inline object", p.inlineObject.properties, func(err error) error {
+				return wrapFn(errorInField(err, p.name))
+			})
+		}
+	}
+}
+
+func (c *documentChecker) checkDiscriminators(definitions []*Definition) {
+	defMap := make(map[string]*Definition, len(definitions))
+	for _, def := range definitions {
+		if _, ok := defMap[def.name]; !ok {
+			defMap[def.name] = def
+		}
+	}
+	for _, def := range definitions {
+		loc := definitionLocation(def) + ".discriminator"
+		if def.discriminator == "" {
+			if len(def.mapping) > 0 {
+				c.error(loc, errorInDefinition(newDocumentError("Discriminator property name is required"), def.name))
+			}
+			continue
+		}
+		if !hasRequiredProperty(def, def.discriminator, defMap, nil) {
+			c.error(loc, errorInDefinition(newDocumentError("Discriminator property `"+def.discriminator+"` must be a required property"), def.name))
+			continue
+		}
+		values := make([]string, 0, len(def.mapping))
+		for value := range def.mapping {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			name := def.mapping[value]
+			sub, ok := defMap[name]
+			if !ok {
+				c.error(fmt.Sprintf("%s.mapping[%s]", loc, value), errorInDefinition(newDocumentError("Discriminator subtype `"+name+"` not found"), def.name))
+				continue
+			}
+			if !hasRequiredProperty(sub, def.discriminator, defMap, nil) {
+				c.error(definitionLocation(sub), errorInDefinition(newDocumentError("Subtype of `"+def.name+"` must have the required discriminator property `"+def.discriminator+"`"), sub.name))
+			}
+		}
+	}
+}
+
+// hasRequiredProperty checks whether given Definition has the required property, including the properties of extended definitions.
+func hasRequiredProperty(def *Definition, name string, defMap map[string]*Definition, visited map[string]bool) bool {
+	if visited == nil {
+		visited = make(map[string]bool)
+	}
+	if visited[def.name] {
+		return false
+	}
+	visited[def.name] = true
+	for _, prop := range def.properties {
+		if prop.name == name {
+			return prop.required
+		}
+	}
+	for _, ext := range def.extends {
+		if idx := strings.Index(ext, "<"); idx != -1 {
+			ext = ext[:idx] // Base<T> -> Base
+		}
+		if extDef, ok := defMap[strings.TrimSpace(ext)]; ok && hasRequiredProperty(extDef, name, defMap, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// ===========
+// type checks
+// ===========
+
+func (c *documentChecker) checkTypes(doc *Document) {
+	// definitions
+	defMap := make(map[string]*Definition, len(doc.definitions))
+	genNames := make(map[*Definition][]string, len(doc.definitions))
+	for _, def := range doc.definitions {
+		loc := definitionLocation(def)
+		if _, ok := defMap[def.name]; ok {
+			c.error(loc, newDocumentError("Duplicate definition `"+def.name+"`"))
+			continue
+		}
+		defMap[def.name] = def
+		for _, decl := range def.generics {
+			gen, err := parseApiGeneric(decl)
+			if err != nil {
+				c.error(fmt.Sprintf("%s.generics[%s]", loc, decl), errorInDefinition(err, def.name))
+				continue
+			}
+			genNames[def] = append(genNames[def], gen.name)
+		}
+	}
+
+	// all types, and used definitions
+	used := make(map[string]bool, len(doc.definitions))
+	checkFn := func(loc, typ string, generics []string, wrapFn func(err error) error) {
+		if typ == "" {
+			return // checked by structure checks
+		}
+		if err := checkApiType(typ); err != nil {
+			c.error(loc, wrapFn(err))
+			return
+		}
+		at, _ := parseApiType(typ)
+		if err := checkObjectType(at, defMap, generics, used); err != nil {
+			c.error(loc, wrapFn(err))
+		}
+	}
+	var checkInlineFn func(loc, typ string, inline *InlineObject, generics []string, wrapFn func(err error) error)
+	checkInlineFn = func(loc, typ string, inline *InlineObject, generics []string, wrapFn func(err error) error) {
+		if inline == nil {
+			checkFn(loc+".type", typ, generics, wrapFn)
+			return
+		}
+		if err := checkInlineObjectType(typ); err != nil {
+			c.error(loc+".type", wrapFn(err))
+		}
+		for _, p := range inline.properties {
+			p := p
+			checkInlineFn(fmt.Sprintf("%s.properties[%s]", loc, p.name), p.typ, p.inlineObject, generics, func(err error) error {
+				return wrapFn(errorInField(err, p.name))
+			})
+		}
+	}
+	for _, op := range doc.operations {
+		loc := operationLocation(op)
+		for _, p := range op.params {
+			p := p
+			checkInlineFn(fmt.Sprintf("%s.params[%s]", loc, p.name), p.typ, p.inlineObject, nil, func(err error) error {
+				return errorInOperation(errorInField(err, p.name), op)
+			})
+		}
+		for _, r := range op.responses {
+			code := strconv.Itoa(r.code)
+			checkInlineFn(fmt.Sprintf("%s.responses[%s]", loc, code), r.typ, r.inlineObject, nil, func(err error) error {
+				return errorInOperation(errorInField(err, code), op)
+			})
+			for _, h := range r.headers {
+				h := h
+				checkFn(fmt.Sprintf("%s.responses[%s].headers[%s].type", loc, code, h.name), h.typ, nil, func(err error) error {
+					return errorInOperation(errorInField(err, h.name), op)
+				})
+			}
+		}
+	}
+	if opt := doc.option; opt != nil {
+		for _, p := range opt.globalParams {
+			p := p
+			checkFn(fmt.Sprintf("option.globalParams[%s].type", p.name), p.typ, nil, func(err error) error {
+				return errorInField(err, p.name)
+			})
+		}
+	}
+	for _, def := range doc.definitions {
+		def := def
+		for _, decl := range def.generics {
+			gen, err := parseApiGeneric(decl) // invalid declaration is checked above
+			if err != nil || gen.defaul == "" {
+				continue
+			}
+			loc := fmt.Sprintf("%s.generics[%s]", definitionLocation(def), gen.name)
+			checkFn(loc, gen.defaul, nil, func(err error) error {
+				return errorInDefinition(errorInField(err, gen.name), def.name)
+			})
+			at, _ := parseApiType(gen.defaul)
+			if err := checkGenericConstraint(def.name, gen, at, defMap); err != nil {
+				c.error(loc, errorInDefinition(err, def.name))
+			}
+		}
+		for _, p := range def.properties {
+			p := p
+			checkInlineFn(fmt.Sprintf("%s.properties[%s]", definitionLocation(def), p.name), p.typ, p.inlineObject, genNames[def], func(err error) error {
+				return errorInDefinition(errorInField(err, p.name), def.name)
+			})
+		}
+		for _, ext := range def.extends {
+			ext := ext
+			if ext == "" {
+				continue // checked by structure checks
+			}
+			loc := fmt.Sprintf("%s.extends[%s]", definitionLocation(def), ext)
+			if err := checkExtendedType(ext); err != nil {
+				c.error(loc, errorInDefinition(errorInField(err, ext), def.name))
+				continue
+			}
+			if extDef, ok := defMap[ext]; ok && extDef.enumType != "" {
+				used[extDef.name] = true
+				c.error(loc, errorInDefinition(errorInField(newDocumentError("Extended type `"+ext+"` must be an object type"), ext), def.name))
+				continue
+			}
+			checkFn(loc, ext, genNames[def], func(err error) error {
+				return errorInDefinition(errorInField(err, ext), def.name)
+			})
+		}
+	}
+
+	// unused definitions, notes that subtypes in discriminator mapping are used
+	for _, def := range doc.definitions {
+		for _, name := range def.mapping {
+			used[name] = true
+		}
+	}
+	for _, def := range doc.definitions {
+		if !used[def.name] {
+			c.warning(definitionLocation(def), errorInDefinition(newDocumentError("Definition `"+def.name+"` is not used"), def.name))
+		}
+	}
+}
+
+// checkObjectType checks the existence and generic parameters of all object types in given apiType, and marks the used definitions.
+func checkObjectType(at *apiType, defMap map[string]*Definition, generics []string, used map[string]bool) error {
+	at = innerApiType(at)
+	if at.kind == apiUnionKind {
+		for _, item := range at.union.items {
+			if err := checkObjectType(item, defMap, generics, used); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if at.kind != apiObjectKind {
+		return nil
+	}
+	obj := at.object
+	for _, gen := range generics {
+		if obj.typ == gen && len(obj.generics) == 0 {
+			return nil // generic parameter
+		}
+	}
+	def, ok := defMap[obj.typ]
+	if !ok {
+		return newDocumentError("Object type `" + at.name + "` not found")
+	}
+	used[def.name] = true
+	genParams, err := parseApiGenerics(def.generics)
+	if err != nil {
+		return nil // invalid declaration is checked in definition
+	}
+	if _, err := resolveGenericArguments(at, genParams); err != nil {
+		return err
+	}
+	for idx, arg := range obj.generics {
+		if err := checkObjectType(arg, defMap, generics, used); err != nil {
+			return err
+		}
+		isParam := false
+		for _, gen := range generics {
+			isParam = isParam || (arg.kind == apiObjectKind && arg.object.typ == gen)
+		}
+		if !isParam { // the constraint of generic parameter is checked when specializing
+			if err := checkGenericConstraint(at.name, genParams[idx], arg, defMap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkGenerics specializes all the generic definitions as generating, to find the growing recursive generics, the conflicts of specialized
+// names and the unsatisfied constraints. It is skipped if any error has been found, which may also fail the specialization.
+func (c *documentChecker) checkGenerics(doc *Document) {
+	for _, issue := range c.issues {
+		if issue.Severity == SEVERITY_ERROR {
+			return
+		}
+	}
+	_, err := prehandleAllDefinitions(doc)
+	if err == nil {
+		return
+	}
+	loc := "definitions"
+	if e, ok := err.(*DocumentError); ok {
+		switch {
+		case e.Method != "" || e.Route != "":
+			loc = fmt.Sprintf("operations[%s %s]", e.Method, e.Route)
+		case e.Definition != "":
+			loc = fmt.Sprintf("definitions[%s]", e.Definition)
+		}
+	}
+	c.error(loc, err)
+}
+
+// =============
+// format checks
+// =============
+
+func (c *documentChecker) checkFormats(doc *Document) {
+	walkDocumentTypes(doc, func(loc, typ string) {
+		if checkApiType(typ) != nil {
+			return // checked by type checks
+		}
+		at, _ := parseApiType(typ)
+		if unknown := checkApiTypeFormats(at); unknown != nil {
+			c.warning(loc, newDocumentError("Format `"+unknown.prime.format+"` of type `"+unknown.prime.typ+"` is not registered"))
+		}
+	})
+}
+
+// walkDocumentTypes visits all the type names in given Document with their locations, including the types of params, responses, headers,
+// properties, extended definitions, generic defaults and enum definitions.
+func walkDocumentTypes(doc *Document, fn func(loc, typ string)) {
+	var walkPropertiesFn func(loc string, properties []*Property)
+	walkPropertiesFn = func(loc string, properties []*Property) {
+		for _, p := range properties {
+			propLoc := fmt.Sprintf("%s.properties[%s]", loc, p.name)
+			if p.inlineObject != nil {
+				walkPropertiesFn(propLoc, p.inlineObject.properties)
+			} else if p.typ != "" {
+				fn(propLoc+".type", p.typ)
+			}
+		}
+	}
+	walkParamsFn := func(loc string, params []*Param) {
+		for _, p := range params {
+			paramLoc := fmt.Sprintf("%s[%s]", loc, p.name)
+			if p.inlineObject != nil {
+				walkPropertiesFn(paramLoc, p.inlineObject.properties)
+			} else if p.typ != "" {
+				fn(paramLoc+".type", p.typ)
+			}
+		}
+	}
+	if opt := doc.option; opt != nil {
+		walkParamsFn("option.globalParams", opt.globalParams)
+	}
+	for _, op := range doc.operations {
+		loc := operationLocation(op)
+		walkParamsFn(loc+".params", op.params)
+		for _, r := range op.responses {
+			respLoc := fmt.Sprintf("%s.responses[%d]", loc, r.code)
+			if r.inlineObject != nil {
+				walkPropertiesFn(respLoc, r.inlineObject.properties)
+			} else if r.typ != "" {
+				fn(respLoc+".type", r.typ)
+			}
+			for _, h := range r.headers {
+				if h.typ != "" {
+					fn(fmt.Sprintf("%s.headers[%s].type", respLoc, h.name), h.typ)
+				}
+			}
+		}
+	}
+	for _, def := range doc.definitions {
+		loc := definitionLocation(def)
+		if def.enumType != "" {
+			fn(loc+".enumType", def.enumType)
+		}
+		for _, decl := range def.generics {
+			if gen, err := parseApiGeneric(decl); err == nil && gen.defaul != "" {
+				fn(fmt.Sprintf("%s.generics[%s]", loc, gen.name), gen.defaul)
+			}
+		}
+		for _, ext := range def.extends {
+			if ext != "" {
+				fn(fmt.Sprintf("%s.extends[%s]", loc, ext), ext)
+			}
+		}
+		walkPropertiesFn(loc, def.properties)
+	}
+}
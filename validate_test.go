@@ -0,0 +1,167 @@
+package goapidoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	doc := NewDocument("", "api", NewInfo("", "", "1.0.0")).
+		Option(NewOption().Tags(NewTag("", ""))).
+		AddOperations(
+			NewOperation("get", "/user/{id}", "").
+				Params(NewPathParam("id", "integer", false, ""), NewQueryParam("q", "integer<T>", true, "")).
+				Responses(NewResponse(200, "Result<User>").Headers(NewResponseHeader("X-Total", "", ""))),
+			NewOperation("post", "/user", "s").
+				Params(NewBodyParam("body", "Unknown", true, "")).
+				Responses(NewResponse(200, "Result<User, User>")),
+		).
+		AddDefinitions(
+			NewDefinition("Result", "").Generics("T").Properties(NewProperty("data", "T[]", true, "")),
+			NewDefinition("User", "").Extends("string", "Base").Properties(NewProperty("id", "integer#int64", true, ""), NewProperty("x", "$", true, "")),
+			NewDefinition("Unused", "").Generics("T-T"),
+			NewDefinition("User", ""),
+		)
+
+	issues := doc.Validate()
+	got := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] host: Host is required",
+		"[error] basePath: BasePath must begin with a slash",
+		"[error] info.title: Info title is required",
+		"[error] option.tags[]: Tag name is required",
+		"[error] operations[GET /user/{id}].summary: Operation summary is required",
+		"[error] operations[GET /user/{id}].params[id].required: Path param's must be non-optional and non-empty",
+		"[error] operations[GET /user/{id}].responses[200].headers[X-Total].type: Response header type is required",
+		"[error] definitions[User].properties[x].type: Invalid type `$`",
+		"[error] definitions[User].extends[string]: Extended type `string` must be an object type",
+		"[error] definitions[User].extends[Base]: Object type `Base` not found",
+		"[error] definitions[User]: Duplicate definition `User`",
+		"[error] definitions[Unused].generics[T-T]: Invalid generic type `T-T`",
+		"[error] operations[GET /user/{id}].params[q].type: Invalid type `integer<T>`",
+		"[error] operations[POST /user].params[body].type: Object type `Unknown` not found",
+		"[error] operations[POST /user].responses[200].type: Object type `Result<User, User>`'s generic parameter length is not matched",
+		"[warning] definitions[Unused]: Definition `Unused` is not used",
+	}, "Validate", "expected issues")
+
+	err := issues[5].err.(*DocumentError)
+	if err.Method != "GET" || err.Route != "/user/{id}" || err.Field != "id" {
+		failNow(t, fmt.Sprintf("Issue error is not expected: %v", err))
+	}
+	doc = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+		NewOperation("get", "/user/{id}", "s").Params(NewPathParam("uid", "integer", true, "")).Responses(NewResponse(200, "")),
+		NewOperation("get", "/user/{uid}", "s").Params(NewPathParam("uid", "integer", true, "")).Responses(NewResponse(200, "")).Tags("user"),
+	)
+	got = got[:0]
+	for _, issue := range doc.Validate() {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] operations[GET /user/{id}].route: Route placeholder `{id}` has no matching path param",
+		"[error] operations[GET /user/{id}].params[uid]: Path param is not found in route",
+		"[error] operations[GET /user/{uid}]: Operation conflicts with `GET /user/{id}`",
+		"[warning] operations[GET /user/{uid}].tags[user]: Tag `user` is not declared in option",
+	}, "Validate", "expected issues")
+	doc = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+		NewOperation("post", "/", "s").
+			Params(NewQueryParam("q", "object", true, "").InlineObject(NewInlineObject()),
+				NewBodyParam("body", "string", true, "").InlineObject(NewInlineObject(NewProperty("id", "", true, "")))).
+			Responses(NewResponse(200, "object").InlineObject(NewInlineObject(NewProperty("x", "Unknown", true, "")))),
+	)
+	got = got[:0]
+	for _, issue := range doc.Validate() {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] operations[POST /].params[q]: Inline object is only supported in body param",
+		"[error] operations[POST /].params[body].properties[id].type: Inline object property type is required",
+		"[error] operations[POST /].params[body].type: Inline object must be used with object type, such as `object` or `object[]`",
+		"[error] operations[POST /].responses[200].properties[x].type: Object type `Unknown` not found",
+	}, "Validate", "expected issues")
+	doc = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+		NewOperation("get", "/", "s").
+			Responses(NewResponse(200, "Result"), NewResponse(201, "Result<integer>"), NewResponse(202, "Page<User>"),
+				NewResponse(203, "Pair<User>"), NewResponse(204, "Any<User>"), NewResponse(205, "Box<Gender[]>")),
+	).AddDefinitions(
+		NewDefinition("Result", "").Generics("T: object").Properties(NewProperty("data", "T", true, "")),
+		NewDefinition("Page", "").Generics("T: object", "U: primitive = User").Properties(NewProperty("data", "T[]", true, "")),
+		NewDefinition("Pair", "").Generics("T", "U").Properties(NewProperty("first", "T", true, ""), NewProperty("second", "U", true, "")),
+		NewDefinition("Box", "").Generics("T: primitive = Gender").Properties(NewProperty("value", "T", true, "")),
+		NewDefinition("Any", "").Generics("T: any"),
+		NewDefinition("User", "").Properties(NewProperty("id", "integer", true, "")),
+		NewEnumDefinition("Gender", "string", "Male", "Female"),
+	)
+	got = got[:0]
+	for _, issue := range doc.Validate() {
+		got = append(got, issue.String())
+	}
+	testMatchElements(t, got, []string{
+		"[error] definitions[Any].generics[T: any]: Generic type `T`'s constraint `any` is not supported",
+		"[error] definitions[Page].generics[U]: Object type `Page`'s generic parameter `U` must be a primitive type",
+		"[error] operations[GET /].responses[201].type: Object type `Result<integer>`'s generic parameter `T` must be an object type",
+		"[error] operations[GET /].responses[203].type: Object type `Pair<User>` misses generic parameter `U` which has no default",
+		"[error] operations[GET /].responses[205].type: Object type `Box<Gender[]>`'s generic parameter `T` must be a primitive type",
+	}, "Validate", "expected issues")
+	for _, tc := range []struct {
+		name     string
+		giveType string
+	}{
+		{"growing recursion", "Node<integer>"},
+		{"constraint in generic body", "Wrapper<integer>"},
+	} {
+		doc = NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+			NewOperation("get", "/", "s").Responses(NewResponse(200, tc.giveType)),
+		).AddDefinitions(
+			NewDefinition("Node", "").Generics("T").Properties(NewProperty("next", "Node<T[]>", false, "")),
+			NewDefinition("Result", "").Generics("T: object").Properties(NewProperty("data", "T", true, "")),
+			NewDefinition("Wrapper", "").Generics("T").Properties(NewProperty("result", "Result<T>", true, "")),
+		)
+		errs := make([]*ValidationIssue, 0, 1)
+		for _, issue := range doc.Validate() {
+			if issue.Severity == SEVERITY_ERROR {
+				errs = append(errs, issue)
+			}
+		}
+		if len(errs) != 1 || !strings.HasPrefix(errs[0].Location, "definitions[") {
+			failNow(t, fmt.Sprintf("Validate get unexpected issues for %s: %v", tc.name, errs))
+		}
+		_, err := doc.GenerateSwaggerJson()
+		testError(t, true, err, "GenerateSwaggerJson")
+	}
+	if len(NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(NewOperation("get", "/", "s").Responses(NewResponse(200, ""))).Validate()) != 0 {
+		failNow(t, "Validate should return no issue for a valid document")
+	}
+}
+
+func TestCheckDocumentWarnings(t *testing.T) {
+	doc := NewDocument("host", "/", NewInfo("title", "", "1.0.0")).AddOperations(
+		NewOperation("get", "/", "s").Tags("usr").Params(NewQueryParam("q", "string#x-unknown", true, "")).Responses(NewResponse(200, "")),
+	)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		failNow(t, "os.Pipe failed: "+err.Error())
+	}
+	os.Stdout = w
+	_, err = doc.GenerateSwaggerJson()
+	os.Stdout = stdout
+	_ = w.Close()
+	out, _ := ioutil.ReadAll(r)
+	testError(t, false, err, "GenerateSwaggerJson")
+
+	for _, want := range []string{
+		"Warning: operations[GET /].tags[usr]: Tag `usr` is not declared in option",
+		"Warning: operations[GET /].params[q].type: Format `x-unknown` of type `string` is not registered",
+	} {
+		if !strings.Contains(string(out), want) {
+			failNow(t, "Warning is not logged when generating: "+want)
+		}
+	}
+}